@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+func (annotation DetailedReportAnnotation) getKey() string {
+	return strings.Join([]string{annotation.Action, annotation.User, annotation.Date, annotation.Description}, "|")
+}
+
+func (annotation DetailedReportAnnotation) getFormattedAction() string {
+	action := strings.ToLower(annotation.Action)
+
+	switch {
+	case strings.Contains(action, "approve"):
+		return color.HiGreenString("Approval")
+	case strings.Contains(action, "reject"):
+		return color.HiRedString("Rejection")
+	case action == "comment":
+		return "Comment"
+	}
+
+	return color.HiYellowString("Proposal \"%s\"", annotation.Action)
+}
+
+// Returns the annotations in this scan which are not in the other scan, oldest first
+func getAnnotationsOnlyInThisScan(thisSideFlaw, otherSideFlaw DetailedReportFlaw) []DetailedReportAnnotation {
+	var otherSideKeys []string
+
+	for _, annotation := range otherSideFlaw.Annotations {
+		otherSideKeys = append(otherSideKeys, annotation.getKey())
+	}
+
+	var annotations []DetailedReportAnnotation
+
+	for _, annotation := range thisSideFlaw.Annotations {
+		if !isStringInStringArray(annotation.getKey(), otherSideKeys) {
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].Date < annotations[j].Date
+	})
+
+	return annotations
+}
+
+// Annotations made before the earlier scan was published or after the later scan was published are not
+// between the scans. Annotations whose dates cannot be parsed are kept
+func getAnnotationsBetween(annotations []DetailedReportAnnotation, from, to time.Time) []DetailedReportAnnotation {
+	var annotationsBetween []DetailedReportAnnotation
+
+	for _, annotation := range annotations {
+		date := tryParseVeracodeDate(annotation.Date)
+
+		if date.IsZero() || (!date.Before(from) && !date.After(to)) {
+			annotationsBetween = append(annotationsBetween, annotation)
+		}
+	}
+
+	return annotationsBetween
+}
+
+func getFormattedAnnotation(annotation DetailedReportAnnotation) string {
+	var formattedDescription = ""
+
+	if len(strings.TrimSpace(annotation.Description)) > 0 {
+		formattedDescription = fmt.Sprintf(": \"%s\"", strings.TrimSpace(annotation.Description))
+	}
+
+	return fmt.Sprintf("  %s %s by %s%s\n", annotation.Date, annotation.getFormattedAction(), annotation.User, formattedDescription)
+}
+
+// Reports the triage activity recorded in the later scan, between the two scans being published, which was not yet recorded in the earlier scan
+func (data Data) reportAnnotationDifferences() {
+	var report strings.Builder
+
+	laterSide, laterReport, earlierReport := "B", data.ScanBReport, data.ScanAReport
+
+	if data.ScanAReport.SubmittedDate.After(data.ScanBReport.SubmittedDate) {
+		laterSide, laterReport, earlierReport = "A", data.ScanAReport, data.ScanBReport
+	}
+
+	for _, laterFlaw := range getFlawsSortedBySeverity(laterReport.Flaws) {
+		earlierFlaw := earlierReport.getMatchingFlaw(laterFlaw)
+		annotations := getAnnotationsBetween(getAnnotationsOnlyInThisScan(laterFlaw, earlierFlaw), earlierReport.PublishedDate, laterReport.PublishedDate)
+
+		if len(annotations) == 0 {
+			continue
+		}
+
+		scanAFlaw, scanBFlaw := earlierFlaw, laterFlaw
+
+		if laterSide == "A" {
+			scanAFlaw, scanBFlaw = laterFlaw, earlierFlaw
+		}
+
+		report.WriteString(fmt.Sprintf("%s %d (%s): %s: %s => %s: %s\n",
+			getFormattedSeverity(laterFlaw.Severity),
+			laterFlaw.ID,
+			getFormattedCwe(laterFlaw.CWE),
+			getFormattedSideString("A"),
+			getFormattedMitigationStatus(scanAFlaw),
+			getFormattedSideString("B"),
+			getFormattedMitigationStatus(scanBFlaw)))
+
+		for _, annotation := range annotations {
+			report.WriteString(getFormattedAnnotation(annotation))
+		}
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Mitigation And Annotation History Between Scans")
+		data.colorPrintf(report.String())
+	}
+}
+
+func getFormattedMitigationStatus(flaw DetailedReportFlaw) string {
+	if flaw.ID == 0 {
+		return "not reported"
+	}
+
+	return cases.Title(language.English).String(flaw.MitigationStatus)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,33 +19,35 @@ type API struct {
 	region string
 }
 
-func (api API) makeApiRequest(apiUrl, httpMethod string) []byte {
+func (api API) getRegionalApiUrl(apiUrl string) string {
 	if api.region == "us" {
-		apiUrl = strings.Replace(apiUrl, ".com", ".us", 1)
-	} else if api.region == "eu" {
-		apiUrl = strings.Replace(apiUrl, ".com", ".eu", 1)
+		return strings.Replace(apiUrl, ".com", ".us", 1)
+	} else if api.region == "european" {
+		return strings.Replace(apiUrl, ".com", ".eu", 1)
 	}
 
-	parsedUrl, err := url.Parse(apiUrl)
+	return apiUrl
+}
+
+// Returns an error rather than exiting, so callers such as the "batch" action can carry on
+func (api API) tryApiRequest(apiUrl, httpMethod string) ([]byte, error) {
+	parsedUrl, err := url.Parse(api.getRegionalApiUrl(apiUrl))
 
 	if err != nil {
-		color.HiRed("Error: Invalid API URL")
-		os.Exit(1)
+		return nil, errors.New("Invalid API URL")
 	}
 
 	client := &http.Client{}
 	req, err := http.NewRequest(httpMethod, parsedUrl.String(), nil)
 
 	if err != nil {
-		color.HiRed("Error: Could not create API request")
-		os.Exit(1)
+		return nil, errors.New("Could not create API request")
 	}
 
 	authorizationHeader, err := hmac.CalculateAuthorizationHeader(parsedUrl, httpMethod, api.id, api.key)
 
 	if err != nil {
-		color.HiRed("Error: Could not calculate the authorization header")
-		os.Exit(1)
+		return nil, errors.New("Could not calculate the authorization header")
 	}
 
 	req.Header.Add("Authorization", authorizationHeader)
@@ -53,34 +56,41 @@ func (api API) makeApiRequest(apiUrl, httpMethod string) []byte {
 	resp, err := client.Do(req)
 
 	if err != nil {
-		color.HiRed("Error: There was a problem communicating with the API. Please check your connectivity and the service status page at https://status.veracode.com")
-		os.Exit(1)
+		return nil, errors.New("There was a problem communicating with the API. Please check your connectivity and the service status page at https://status.veracode.com")
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode == 401 {
 		if strings.HasSuffix(parsedUrl.Path, "getmaintenancescheduleinfo.do") {
-			color.HiRed("Error: There was a problem with your credentials. Please check your credentials are valid for this Veracode region. For help contact your Veracode administrator.")
-		} else {
-			color.HiRed("Error: You are not authorized to perform this action. Please check you have the \"Results API\" user role set. For help contact your Veracode administrator and refer to https://docs.veracode.com/r/c_API_roles_details")
+			return nil, errors.New("There was a problem with your credentials. Please check your credentials are valid for this Veracode region. For help contact your Veracode administrator.")
 		}
 
-		os.Exit(1)
+		return nil, errors.New("You are not authorized to perform this action. Please check you have the \"Results API\" user role set. For help contact your Veracode administrator and refer to https://docs.veracode.com/r/c_API_roles_details")
 	}
 
 	if resp.StatusCode == 403 {
-		color.HiRed("Error: This request was forbidden. Ensure you can view these scans within the Veracode Platform. For help contact your Veracode administrator and refer to https://docs.veracode.com/r/c_API_roles_details")
-		os.Exit(1)
+		return nil, errors.New("This request was forbidden. Ensure you can view these scans within the Veracode Platform. For help contact your Veracode administrator and refer to https://docs.veracode.com/r/c_API_roles_details")
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		color.HiRed(fmt.Sprintf("Error: API request returned status of %s", resp.Status))
-		os.Exit(1)
+		return nil, fmt.Errorf("API request returned status of %s", resp.Status)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 
 	if err != nil {
-		color.HiRed("Error: There was a problem processing the API response. Please check your connectivity and the service status page at https://status.veracode.com")
+		return nil, errors.New("There was a problem processing the API response. Please check your connectivity and the service status page at https://status.veracode.com")
+	}
+
+	return body, nil
+}
+
+func (api API) makeApiRequest(apiUrl, httpMethod string) []byte {
+	body, err := api.tryApiRequest(apiUrl, httpMethod)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
 		os.Exit(1)
 	}
 
@@ -90,3 +100,9 @@ func (api API) makeApiRequest(apiUrl, httpMethod string) []byte {
 func (api API) assertCredentialsWork() {
 	api.makeApiRequest("https://analysiscenter.veracode.com/api/3.0/getmaintenancescheduleinfo.do", http.MethodGet)
 }
+
+// Like assertCredentialsWork but returns the reason instead of exiting, so the caller can fall back to other credentials
+func (api API) checkCredentialsWork() error {
+	_, err := api.tryApiRequest("https://analysiscenter.veracode.com/api/3.0/getmaintenancescheduleinfo.do", http.MethodGet)
+	return err
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+type BatchPair struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+type BatchResult struct {
+	Pair              BatchPair `json:"pair"`
+	ScanABuildId      int       `json:"scan_a_build_id"`
+	ScanBBuildId      int       `json:"scan_b_build_id"`
+	FlawsOnlyInA      int       `json:"flaws_only_in_a"`
+	FlawsOnlyInB      int       `json:"flaws_only_in_b"`
+	FlawStateChanges  int       `json:"flaw_state_changes"`
+	ModuleDifferences int       `json:"module_differences"`
+	ReportPath        string    `json:"report_path,omitempty"`
+	Error             string    `json:"error,omitempty"`
+}
+
+var ansiEscapeSequenceRegex = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func runBatch(vid, vkey, profile, region, batchFile, outputDir, format string, concurrency int, matchMode string, flawFilter FlawFilter, riskModel RiskModel, codeOwners CodeOwners, pathRules PathRules) {
+	if len(batchFile) < 1 {
+		color.HiRed("Error: No batch file specified. Expected: \"scan_compare -action batch -batch-file pairs.csv\"")
+		print("\nUsage:\n")
+		flag.PrintDefaults()
+		return
+	}
+
+	if !(format == "text" || format == "json") {
+		color.HiRed("Error: Invalid format. Must be either \"text\" or \"json\"")
+		os.Exit(1)
+	}
+
+	if concurrency < 1 {
+		color.HiRed("Error: Invalid value for -concurrency. Must be at least 1")
+		os.Exit(1)
+	}
+
+	pairs := parseBatchFile(batchFile)
+
+	if len(pairs) == 0 {
+		color.HiRed(fmt.Sprintf("Error: No scan pairs found in \"%s\"", batchFile))
+		os.Exit(1)
+	}
+
+	var urls []string
+
+	for _, pair := range pairs {
+		for _, reference := range []string{pair.A, pair.B} {
+			assertValidScanReference(reference)
+
+			if strings.HasPrefix(reference, "https://") {
+				urls = append(urls, reference)
+			}
+		}
+	}
+
+	for _, url := range urls {
+		if parseRegionFromUrl(url) != parseRegionFromUrl(urls[0]) {
+			color.HiRed("Error: Cannot compare between different Veracode regions")
+			os.Exit(1)
+		}
+	}
+
+	regionToUse := region
+
+	if len(urls) > 0 {
+		regionToUse = getRegionToUse(region, urls...)
+	} else if region == "" {
+		regionToUse = "commercial"
+	}
+
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not create the output directory \"%s\"", outputDir))
+		os.Exit(1)
+	}
+
+	api := getApi(vid, vkey, profile, regionToUse)
+
+	colorPrintf(fmt.Sprintf("Comparing %d scan pairs in the %s region with a concurrency of %d\n", len(pairs), api.region, concurrency))
+
+	results := make([]BatchResult, len(pairs))
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for index, pair := range pairs {
+		wg.Add(1)
+
+		go func(index int, pair BatchPair) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[index] = api.runBatchPair(index+1, pair, outputDir, format, matchMode, flawFilter, riskModel, codeOwners, pathRules)
+
+			if len(results[index].Error) > 0 {
+				color.HiRed(fmt.Sprintf("Pair %d: %s", index+1, results[index].Error))
+			} else {
+				fmt.Printf("Pair %d: Report written to %s\n", index+1, results[index].ReportPath)
+			}
+		}(index, pair)
+	}
+
+	wg.Wait()
+
+	reportBatchSummary(results)
+	writeBatchSummary(results, outputDir, format)
+}
+
+func parseBatchFile(batchFile string) []BatchPair {
+	content, err := os.ReadFile(batchFile)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not read the batch file \"%s\"", batchFile))
+		os.Exit(1)
+	}
+
+	var pairs []BatchPair
+
+	if strings.EqualFold(filepath.Ext(batchFile), ".json") {
+		if err := json.Unmarshal(content, &pairs); err != nil {
+			color.HiRed(fmt.Sprintf("Error: Could not parse the batch file \"%s\". Expected a JSON array of objects with \"a\" and \"b\" properties", batchFile))
+			os.Exit(1)
+		}
+
+		return pairs
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not parse the batch file \"%s\". Expected CSV rows of \"a,b\"", batchFile))
+		os.Exit(1)
+	}
+
+	for index, record := range records {
+		if len(record) != 2 {
+			color.HiRed(fmt.Sprintf("Error: Line %d of the batch file \"%s\" does not contain exactly two columns", index+1, batchFile))
+			os.Exit(1)
+		}
+
+		// Skip the optional header row
+		if index == 0 && strings.EqualFold(record[0], "a") && strings.EqualFold(record[1], "b") {
+			continue
+		}
+
+		pairs = append(pairs, BatchPair{strings.TrimSpace(record[0]), strings.TrimSpace(record[1])})
+	}
+
+	return pairs
+}
+
+// Scan references can be Platform URLs, build IDs or one of these selectors:
+// "policy:<app id>" for the latest policy scan with results
+// "sandbox:<app id>:<sandbox name>" for the latest sandbox scan with results
+func assertValidScanReference(reference string) {
+	if strings.HasPrefix(reference, "policy:") || strings.HasPrefix(reference, "sandbox:") {
+		if _, _, err := parseScanSelector(reference); err != nil {
+			color.HiRed(fmt.Sprintf("Error: %s", err))
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if _, err := parseBuildIdFromPlatformUrl(reference); err != nil {
+		platformUrlInvalid(reference, err)
+	}
+}
+
+func parseScanSelector(selector string) (int, string, error) {
+	parts := strings.SplitN(selector, ":", 3)
+
+	if len(parts) < 2 {
+		return 0, "", fmt.Errorf("\"%s\" is not a valid selector", selector)
+	}
+
+	appId, err := strconv.Atoi(parts[1])
+
+	if err != nil {
+		return 0, "", fmt.Errorf("\"%s\" is not a valid selector. The application ID must be a number", selector)
+	}
+
+	if parts[0] == "policy" && len(parts) == 2 {
+		return appId, "", nil
+	}
+
+	if parts[0] == "sandbox" && len(parts) == 3 && len(parts[2]) > 0 {
+		return appId, parts[2], nil
+	}
+
+	return 0, "", fmt.Errorf("\"%s\" is not a valid selector. Expected \"policy:<app id>\" or \"sandbox:<app id>:<sandbox name>\"", selector)
+}
+
+func (api API) resolveScanReference(reference string) (int, error) {
+	if !(strings.HasPrefix(reference, "policy:") || strings.HasPrefix(reference, "sandbox:")) {
+		return parseBuildIdFromPlatformUrl(reference)
+	}
+
+	appId, sandboxName, err := parseScanSelector(reference)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var sandboxId = 0
+
+	if len(sandboxName) > 0 {
+		sandboxList, err := api.tryGetSandboxList(appId)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for _, sandbox := range sandboxList.Sandboxes {
+			if sandbox.Name == sandboxName {
+				sandboxId = sandbox.ID
+			}
+		}
+
+		if sandboxId == 0 {
+			return 0, fmt.Errorf("could not find a sandbox named \"%s\" for application id %d", sandboxName, appId)
+		}
+	}
+
+	buildId, err := api.tryGetLatestBuildIdWithResults(appId, sandboxId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if buildId == 0 {
+		return 0, fmt.Errorf("could not find a scan with results for \"%s\"", reference)
+	}
+
+	return buildId, nil
+}
+
+func (api API) runBatchPair(pairNumber int, pair BatchPair, outputDir, format, matchMode string, flawFilter FlawFilter, riskModel RiskModel, codeOwners CodeOwners, pathRules PathRules) BatchResult {
+	result := BatchResult{Pair: pair}
+
+	scanABuildId, err := api.resolveScanReference(pair.A)
+
+	if err != nil {
+		result.Error = fmt.Sprintf("Scan A: %s", err)
+		return result
+	}
+
+	scanBBuildId, err := api.resolveScanReference(pair.B)
+
+	if err != nil {
+		result.Error = fmt.Sprintf("Scan B: %s", err)
+		return result
+	}
+
+	result.ScanABuildId = scanABuildId
+	result.ScanBBuildId = scanBBuildId
+
+	if scanABuildId == scanBBuildId {
+		result.Error = "These are both the same scan"
+		return result
+	}
+
+	data, err := tryGetData(api, api, scanABuildId, scanBBuildId, matchMode, pathRules)
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	data.applyFlawFilter(flawFilter)
+	data.RiskModel = riskModel
+	data.CodeOwners = codeOwners
+
+	if len(data.ScanAPrescanModuleList.Modules) == 0 || len(data.ScanBPrescanModuleList.Modules) == 0 {
+		result.Error = "Could not retrieve pre-scan modules"
+		return result
+	}
+
+	jsonReport := data.getJsonReport()
+
+	result.FlawsOnlyInA = len(jsonReport.FlawsOnlyInA)
+	result.FlawsOnlyInB = len(jsonReport.FlawsOnlyInB)
+	result.FlawStateChanges = len(jsonReport.FlawStateChanges)
+	result.ModuleDifferences = len(jsonReport.SelectedModulesOnlyInA) + len(jsonReport.SelectedModulesOnlyInB) + len(jsonReport.ChangedFiles)
+	result.ReportPath = filepath.Join(outputDir, fmt.Sprintf("pair-%03d-%d-vs-%d.%s", pairNumber, scanABuildId, scanBBuildId, map[string]string{"text": "txt", "json": "json"}[format]))
+
+	if format == "json" {
+		err = writeJsonFile(result.ReportPath, jsonReport)
+	} else {
+		err = writeTextReportToFile(result.ReportPath, func(output io.Writer) {
+			data.Output = output
+			data.reportOnWarnings(pair.A, pair.B)
+			data.report()
+		})
+	}
+
+	if err != nil {
+		result.Error = fmt.Sprintf("Could not write the report to \"%s\"", result.ReportPath)
+	}
+
+	return result
+}
+
+func writeJsonFile(path string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0640)
+}
+
+// Report files are plain text, so colours are stripped as the report is written
+type plainTextWriter struct {
+	writer io.Writer
+}
+
+func (w plainTextWriter) Write(p []byte) (int, error) {
+	if _, err := w.writer.Write(ansiEscapeSequenceRegex.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func writeTextReportToFile(path string, render func(output io.Writer)) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	render(plainTextWriter{file})
+
+	return file.Close()
+}
+
+func (result BatchResult) hasDifferences() bool {
+	return result.FlawsOnlyInA > 0 || result.FlawsOnlyInB > 0 || result.FlawStateChanges > 0 || result.ModuleDifferences > 0
+}
+
+func reportBatchSummary(results []BatchResult) {
+	var report strings.Builder
+	var pairsWithDifferences = 0
+
+	for index, result := range results {
+		var formattedPair = fmt.Sprintf("Pair %d (%s vs %s)", index+1, result.Pair.A, result.Pair.B)
+
+		if len(result.Error) > 0 {
+			report.WriteString(color.HiRedString("%s: Error: %s\n", formattedPair, result.Error))
+			continue
+		}
+
+		if !result.hasDifferences() {
+			report.WriteString(fmt.Sprintf("%s: No flaw or module differences\n", formattedPair))
+			continue
+		}
+
+		pairsWithDifferences++
+
+		report.WriteString(color.HiYellowString("%s: %d flaws only in A, %d flaws only in B, %d flaw state changes, %d module differences\n",
+			formattedPair,
+			result.FlawsOnlyInA,
+			result.FlawsOnlyInB,
+			result.FlawStateChanges,
+			result.ModuleDifferences))
+	}
+
+	report.WriteString(fmt.Sprintf("\n%d of %d pairs had flaw or module differences\n", pairsWithDifferences, len(results)))
+
+	printTitle("Batch Summary")
+	colorPrintf(report.String())
+}
+
+func writeBatchSummary(results []BatchResult, outputDir, format string) {
+	var summaryPath string
+	var err error
+
+	if format == "json" {
+		summaryPath = filepath.Join(outputDir, "summary.json")
+		err = writeJsonFile(summaryPath, results)
+	} else {
+		summaryPath = filepath.Join(outputDir, "summary.csv")
+		err = writeBatchSummaryCsv(summaryPath, results)
+	}
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not write the batch summary to \"%s\"", summaryPath))
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nBatch summary written to %s\n", summaryPath)
+}
+
+func writeBatchSummaryCsv(path string, results []BatchResult) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"a", "b", "scan_a_build_id", "scan_b_build_id", "flaws_only_in_a", "flaws_only_in_b", "flaw_state_changes", "module_differences", "report_path", "error"})
+
+	for _, result := range results {
+		writer.Write([]string{
+			result.Pair.A,
+			result.Pair.B,
+			strconv.Itoa(result.ScanABuildId),
+			strconv.Itoa(result.ScanBBuildId),
+			strconv.Itoa(result.FlawsOnlyInA),
+			strconv.Itoa(result.FlawsOnlyInB),
+			strconv.Itoa(result.FlawStateChanges),
+			strconv.Itoa(result.ModuleDifferences),
+			result.ReportPath,
+			result.Error})
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
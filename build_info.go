@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+)
+
+type BuildList struct {
+	XMLName xml.Name         `xml:"buildlist"`
+	Builds  []BuildListBuild `xml:"build"`
+}
+
+type BuildListBuild struct {
+	XMLName xml.Name `xml:"build"`
+	ID      int      `xml:"build_id,attr"`
+	Version string   `xml:"version,attr"`
+}
+
+type BuildInfo struct {
+	XMLName   xml.Name       `xml:"buildinfo"`
+	AccountId int            `xml:"account_id,attr"`
+	AppId     int            `xml:"app_id,attr"`
+	SandboxId int            `xml:"sandbox_id,attr"`
+	Build     BuildInfoBuild `xml:"build"`
+}
+
+type BuildInfoBuild struct {
+	XMLName      xml.Name `xml:"build"`
+	ID           int      `xml:"build_id,attr"`
+	Version      string   `xml:"version,attr"`
+	ResultsReady bool     `xml:"results_ready,attr"`
+}
+
+func (api API) tryGetBuildList(appId, sandboxId int) (BuildList, error) {
+	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/getbuildlist.do?app_id=%d", appId)
+
+	if sandboxId > 0 {
+		url = fmt.Sprintf("%s&sandbox_id=%d", url, sandboxId)
+	}
+
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return BuildList{}, err
+	}
+
+	buildList := BuildList{}
+	xml.Unmarshal(response, &buildList)
+
+	// Sort builds by ID, newest first
+	sort.Slice(buildList.Builds, func(i, j int) bool {
+		return buildList.Builds[i].ID > buildList.Builds[j].ID
+	})
+
+	return buildList, nil
+}
+
+func (api API) tryGetBuildInfo(appId, buildId int) (BuildInfo, error) {
+	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/getbuildinfo.do?app_id=%d&build_id=%d", appId, buildId)
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return BuildInfo{}, err
+	}
+
+	buildInfo := BuildInfo{}
+	xml.Unmarshal(response, &buildInfo)
+
+	return buildInfo, nil
+}
+
+// Returns the ID of the newest build with results, or 0 if there is none
+func (api API) getLatestBuildIdWithResults(appId, sandboxId int) int {
+	buildId, err := api.tryGetLatestBuildIdWithResults(appId, sandboxId)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
+		os.Exit(1)
+	}
+
+	return buildId
+}
+
+func (api API) tryGetLatestBuildIdWithResults(appId, sandboxId int) (int, error) {
+	buildList, err := api.tryGetBuildList(appId, sandboxId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, build := range buildList.Builds {
+		buildInfo, err := api.tryGetBuildInfo(appId, build.ID)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if buildInfo.Build.ResultsReady {
+			return build.ID, nil
+		}
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+type CallStacks struct {
+	XMLName    xml.Name    `xml:"callstacks"`
+	CallStacks []CallStack `xml:"callstack"`
+}
+
+type CallStack struct {
+	XMLName    xml.Name        `xml:"callstack"`
+	ModuleName string          `xml:"module_name,attr"`
+	Calls      []CallStackCall `xml:"call"`
+}
+
+type CallStackCall struct {
+	XMLName      xml.Name `xml:"call"`
+	DataPath     int      `xml:"data_path,attr"`
+	FileName     string   `xml:"file_name,attr"`
+	FilePath     string   `xml:"file_path,attr"`
+	FunctionName string   `xml:"function_name,attr"`
+	LineNumber   int      `xml:"line_number,attr"`
+}
+
+func (api API) tryGetCallStacks(buildId, flawId int) (CallStacks, error) {
+	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/getcallstacks.do?build_id=%d&flaw_id=%d", buildId, flawId)
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return CallStacks{}, err
+	}
+
+	callStacks := CallStacks{}
+
+	if err := xml.Unmarshal(response, &callStacks); err != nil {
+		return CallStacks{}, fmt.Errorf("Could not parse the call stacks for flaw %d in build id %d", flawId, buildId)
+	}
+
+	return callStacks, nil
+}
+
+// Frames are identified by function and file so a frame which only moved line is reported as changed
+func (call CallStackCall) getKey() string {
+	return fmt.Sprintf("%s (%s%s)", call.FunctionName, call.FilePath, call.FileName)
+}
+
+// Returns the line numbers of each frame across all data paths
+func (callStacks CallStacks) getFrames() map[string][]int {
+	frames := make(map[string][]int)
+
+	for _, callStack := range callStacks.CallStacks {
+		for _, call := range callStack.Calls {
+			frames[call.getKey()] = append(frames[call.getKey()], call.LineNumber)
+		}
+	}
+
+	for key := range frames {
+		sort.Ints(frames[key])
+	}
+
+	return frames
+}
+
+// Call stacks are only worth fetching for flaws in both scans which the engine may have found by a different data path
+func (data Data) getFlawsNeedingCallStacks() []DetailedReportFlaw {
+	var flaws []DetailedReportFlaw
+
+	for _, scanAFlaw := range getFlawsSortedBySeverity(data.ScanAReport.Flaws) {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+
+		if scanBFlaw.ID == 0 {
+			continue
+		}
+
+		if scanAFlaw.LineNumber != scanBFlaw.LineNumber || scanAFlaw.Module != scanBFlaw.Module || scanAFlaw.RemediationStatus != scanBFlaw.RemediationStatus {
+			flaws = append(flaws, scanAFlaw)
+		}
+	}
+
+	return flaws
+}
+
+// Requires one API call per flaw per scan, so this is only done when requested
+func (data *Data) fetchCallStacks(scanAApi, scanBApi API) {
+	data.ScanACallStacks = make(map[int]CallStacks)
+	data.ScanBCallStacks = make(map[int]CallStacks)
+
+	for _, scanAFlaw := range data.getFlawsNeedingCallStacks() {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		var scanACallStacks, scanBCallStacks CallStacks
+		var scanAErr, scanBErr error
+
+		go func() {
+			defer wg.Done()
+			scanACallStacks, scanAErr = scanAApi.tryGetCallStacks(data.ScanAReport.BuildId, scanAFlaw.ID)
+		}()
+
+		go func() {
+			defer wg.Done()
+			scanBCallStacks, scanBErr = scanBApi.tryGetCallStacks(data.ScanBReport.BuildId, scanBFlaw.ID)
+		}()
+
+		wg.Wait()
+
+		// One flaw's call stacks failing should not lose the rest of the comparison
+		if scanAErr != nil || scanBErr != nil {
+			data.CallStacksSkipped = append(data.CallStacksSkipped, scanAFlaw.ID)
+			continue
+		}
+
+		data.PathRules.normaliseCallStackPaths(&scanACallStacks)
+		data.PathRules.normaliseCallStackPaths(&scanBCallStacks)
+		data.ScanACallStacks[scanAFlaw.ID] = scanACallStacks
+		data.ScanBCallStacks[scanBFlaw.ID] = scanBCallStacks
+	}
+}
+
+func getFormattedLineNumbers(lineNumbers []int) string {
+	var formatted []string
+
+	for _, lineNumber := range lineNumbers {
+		formatted = append(formatted, fmt.Sprintf("%d", lineNumber))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// Returns the frame differences and whether any frames were added or removed
+func compareCallStacks(scanACallStacks, scanBCallStacks CallStacks) (string, bool) {
+	var report strings.Builder
+	var isDifferentDataPath = false
+
+	scanAFrames := scanACallStacks.getFrames()
+	scanBFrames := scanBCallStacks.getFrames()
+
+	var keys []string
+
+	for key := range scanAFrames {
+		keys = append(keys, key)
+	}
+
+	for key := range scanBFrames {
+		if _, found := scanAFrames[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		scanALines, inScanA := scanAFrames[key]
+		scanBLines, inScanB := scanBFrames[key]
+
+		switch {
+		case !inScanB:
+			isDifferentDataPath = true
+			report.WriteString(fmt.Sprintf("  %s %s line %s\n", color.HiGreenString("- Removed:"), key, getFormattedLineNumbers(scanALines)))
+		case !inScanA:
+			isDifferentDataPath = true
+			report.WriteString(fmt.Sprintf("  %s %s line %s\n", color.HiRedString("+ Added:  "), key, getFormattedLineNumbers(scanBLines)))
+		case getFormattedLineNumbers(scanALines) != getFormattedLineNumbers(scanBLines):
+			report.WriteString(fmt.Sprintf("  %s %s line %s => %s\n", color.HiYellowString("~ Changed:"), key, getFormattedLineNumbers(scanALines), getFormattedLineNumbers(scanBLines)))
+		}
+	}
+
+	return report.String(), isDifferentDataPath
+}
+
+func (data Data) reportCallStackDifferences() {
+	if data.ScanACallStacks == nil {
+		return
+	}
+
+	var report strings.Builder
+
+	for _, scanAFlaw := range data.getFlawsNeedingCallStacks() {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+		scanACallStacks, found := data.ScanACallStacks[scanAFlaw.ID]
+
+		if !found {
+			continue
+		}
+
+		scanBCallStacks := data.ScanBCallStacks[scanBFlaw.ID]
+
+		if len(scanACallStacks.CallStacks) == 0 && len(scanBCallStacks.CallStacks) == 0 {
+			continue
+		}
+
+		frameDifferences, isDifferentDataPath := compareCallStacks(scanACallStacks, scanBCallStacks)
+
+		var verdict = color.HiGreenString("same data path")
+
+		if isDifferentDataPath {
+			verdict = color.HiYellowString("different data path")
+		} else if len(frameDifferences) > 0 {
+			verdict = color.HiGreenString("same data path, line numbers changed")
+		}
+
+		report.WriteString(fmt.Sprintf("%s %d (%s): %s\n", getFormattedSeverity(scanAFlaw.Severity), scanAFlaw.ID, getFormattedCwe(scanAFlaw.CWE), verdict))
+		report.WriteString(frameDifferences)
+	}
+
+	if len(data.CallStacksSkipped) > 0 {
+		var skipped []string
+
+		for _, flawId := range data.CallStacksSkipped {
+			skipped = append(skipped, fmt.Sprintf("%d", flawId))
+		}
+
+		report.WriteString(color.HiYellowString(fmt.Sprintf("\nThe call stacks could not be fetched for %d flaw(s), which were skipped: %s\n", len(skipped), strings.Join(skipped, ", "))))
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Call Stack Differences")
+		data.colorPrintf(report.String())
+	}
+}
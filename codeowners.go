@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const unownedFlawOwner = "(unowned)"
+
+type CodeOwnersRule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+
+	// Whether the pattern can name a directory which owns everything within it
+	matchesDirectory bool
+}
+
+type CodeOwners struct {
+	Rules []CodeOwnersRule
+}
+
+// Flaw source paths are often relative to a build output or package root rather than the repository root.
+// They are matched after the -path-rules have been applied, so a replacement can map them onto the repository.
+// Folding case lower-cases those paths, so the patterns must then be matched case-insensitively too
+func parseCodeOwners(codeOwnersFile string, foldCase bool) CodeOwners {
+	codeOwners := CodeOwners{}
+
+	if len(codeOwnersFile) == 0 {
+		return codeOwners
+	}
+
+	content, err := os.ReadFile(codeOwnersFile)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not read the CODEOWNERS file \"%s\"", codeOwnersFile))
+		os.Exit(1)
+	}
+
+	if foldCase {
+		color.HiYellow(fmt.Sprintf("Warning: The path rules fold case, so the patterns in the CODEOWNERS file \"%s\" will be matched case-insensitively", codeOwnersFile))
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+
+		// Sections such as "[Frontend]" are a GitLab extension and are skipped
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+
+		var owners []string
+
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+
+			owners = append(owners, owner)
+		}
+
+		codeOwners.Rules = append(codeOwners.Rules, parseCodeOwnersRule(fields[0], owners, foldCase))
+	}
+
+	return codeOwners
+}
+
+// A pattern ending in a name without wildcards, such as "logs" or "**/logs", matches a file or a directory of that name.
+// Patterns ending in "/" already match everything within the directory, and "docs/*" only matches the files directly in "docs"
+func parseCodeOwnersRule(pattern string, owners []string, ignoreCase bool) CodeOwnersRule {
+	name := pattern[strings.LastIndex(pattern, "/")+1:]
+
+	return CodeOwnersRule{
+		Pattern:          pattern,
+		Owners:           owners,
+		regex:            codeOwnersPatternToRegex(pattern, ignoreCase),
+		matchesDirectory: len(name) > 0 && !strings.ContainsAny(name, "*?["),
+	}
+}
+
+// Patterns follow the gitignore rules used by CODEOWNERS: a leading or inner "/" anchors the pattern
+// to the repository root, otherwise it can match at any depth. As on GitHub, matching is case-sensitive unless ignoring case
+func codeOwnersPatternToRegex(pattern string, ignoreCase bool) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+
+	return globToRegex(pattern, ignoreCase)
+}
+
+func (rule CodeOwnersRule) matches(path string) bool {
+	if rule.regex.MatchString(path) {
+		return true
+	}
+
+	if !rule.matchesDirectory {
+		return false
+	}
+
+	for index := strings.LastIndex(path, "/"); index > 0; index = strings.LastIndex(path[:index], "/") {
+		if rule.regex.MatchString(path[:index]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (codeOwners CodeOwners) isEmpty() bool {
+	return len(codeOwners.Rules) == 0
+}
+
+// The last matching rule wins, as it does on GitHub and GitLab
+func (codeOwners CodeOwners) getOwners(flaw DetailedReportFlaw) []string {
+	path := flaw.getSourceFilePath()
+
+	for index := len(codeOwners.Rules) - 1; index >= 0; index-- {
+		rule := codeOwners.Rules[index]
+
+		if rule.matches(path) {
+			if len(rule.Owners) == 0 {
+				break
+			}
+
+			return rule.Owners
+		}
+	}
+
+	return []string{unownedFlawOwner}
+}
+
+type ownerFlawDifferences struct {
+	newFlaws    []DetailedReportFlaw
+	closedFlaws []DetailedReportFlaw
+}
+
+// New flaws are open in B but not open in A, closed flaws are open in A but not open in B
+func (data Data) getFlawDifferencesByOwner() map[string]*ownerFlawDifferences {
+	differences := make(map[string]*ownerFlawDifferences)
+
+	getDifferences := func(owner string) *ownerFlawDifferences {
+		if _, found := differences[owner]; !found {
+			differences[owner] = &ownerFlawDifferences{}
+		}
+
+		return differences[owner]
+	}
+
+	for _, flaw := range getFlawsSortedBySeverity(getFlawsClosedInOtherScan(data.ScanBReport, data.ScanAReport)) {
+		for _, owner := range data.CodeOwners.getOwners(flaw) {
+			getDifferences(owner).newFlaws = append(getDifferences(owner).newFlaws, flaw)
+		}
+	}
+
+	for _, flaw := range getFlawsSortedBySeverity(getFlawsClosedInOtherScan(data.ScanAReport, data.ScanBReport)) {
+		for _, owner := range data.CodeOwners.getOwners(flaw) {
+			getDifferences(owner).closedFlaws = append(getDifferences(owner).closedFlaws, flaw)
+		}
+	}
+
+	return differences
+}
+
+// Unowned flaws are listed last
+func getSortedOwners(differences map[string]*ownerFlawDifferences) []string {
+	var owners []string
+
+	for owner := range differences {
+		owners = append(owners, owner)
+	}
+
+	sort.Slice(owners, func(i, j int) bool {
+		if (owners[i] == unownedFlawOwner) != (owners[j] == unownedFlawOwner) {
+			return owners[j] == unownedFlawOwner
+		}
+
+		return owners[i] < owners[j]
+	})
+
+	return owners
+}
+
+func (data Data) reportFlawDifferencesByOwner() {
+	if data.CodeOwners.isEmpty() {
+		return
+	}
+
+	differences := data.getFlawDifferencesByOwner()
+
+	if len(differences) == 0 {
+		return
+	}
+
+	var report strings.Builder
+
+	writeFlaw := func(label string, flaw DetailedReportFlaw) {
+		report.WriteString(fmt.Sprintf("  %s %s %d (%s): %s\n", label, getFormattedSeverity(flaw.Severity), flaw.ID, getFormattedCwe(flaw.CWE), flaw.getSourceFilePath()))
+	}
+
+	for _, owner := range getSortedOwners(differences) {
+		report.WriteString(fmt.Sprintf("%s: %s, %s\n",
+			color.HiCyanString(owner),
+			color.HiRedString("%d new", len(differences[owner].newFlaws)),
+			color.HiGreenString("%d closed", len(differences[owner].closedFlaws))))
+
+		for _, flaw := range differences[owner].newFlaws {
+			writeFlaw(color.HiRedString("New:   "), flaw)
+		}
+
+		for _, flaw := range differences[owner].closedFlaws {
+			writeFlaw(color.HiGreenString("Closed:"), flaw)
+		}
+	}
+
+	data.printTitle("Flaw Differences By Owner")
+	data.colorPrintf(report.String())
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCodeOwnersRuleMatches(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		path       string
+		ignoreCase bool
+		expected   bool
+	}{
+		{"docs/*", "docs/a.md", false, true},
+		{"docs/*", "docs/a/b.md", false, false},
+		{"docs/*", "src/docs/a.md", false, false},
+		{"docs/", "docs/a.md", false, true},
+		{"docs/", "docs/a/b.md", false, true},
+		{"docs/", "src/docs/a.md", false, true},
+		{"/build/logs/", "build/logs/a.log", false, true},
+		{"/build/logs/", "build/logs/2024/a.log", false, true},
+		{"/build/logs/", "src/build/logs/a.log", false, false},
+		{"*.js", "app.js", false, true},
+		{"*.js", "src/app/app.js", false, true},
+		{"*.js", "app.jsx", false, false},
+		{"*.js", "src/app.js/a.ts", false, false},
+		{"**/logs", "logs", false, true},
+		{"**/logs", "logs/a.log", false, true},
+		{"**/logs", "src/logs/2024/a.log", false, true},
+		{"**/logs", "src/catalogs/a.log", false, false},
+		{"logs", "src/logs/a.log", false, true},
+		{"/src/Login.java", "src/Login.java", false, true},
+		{"/src/Login.java", "lib/src/Login.java", false, false},
+		{"/src/Login.java", "src/login.java", false, false},
+		{"/src/Login.java", "src/login.java", true, true},
+		{"/Docs/", "docs/a.md", true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.path, func(t *testing.T) {
+			if result := parseCodeOwnersRule(test.pattern, nil, test.ignoreCase).matches(test.path); result != test.expected {
+				t.Errorf("got %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestCodeOwnersGetOwners(t *testing.T) {
+	codeOwners := CodeOwners{Rules: []CodeOwnersRule{
+		parseCodeOwnersRule("*", []string{"@everyone"}, false),
+		parseCodeOwnersRule("/src/", []string{"@backend"}, false),
+		parseCodeOwnersRule("/src/generated/", nil, false),
+	}}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"README.md", "@everyone"},
+		{"src/Login.java", "@backend"},
+		{"src/generated/Api.java", unownedFlawOwner},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			flaw := DetailedReportFlaw{SourceFile: test.path}
+
+			if owners := codeOwners.getOwners(flaw); len(owners) != 1 || owners[0] != test.expected {
+				t.Errorf("got %v, expected [%s]", owners, test.expected)
+			}
+		})
+	}
+}
@@ -12,10 +12,18 @@ import (
 func (data Data) reportFlawDifferences() {
 	data.reportFlawStateDifferences()
 	data.reportFlawMitigationDifferences()
+	data.reportAnnotationDifferences()
+	data.reportFlawSeverityDifferences()
 	data.reportFlawLineNumberChanges()
+	data.reportMovedFlaws()
+	data.reportCallStackDifferences()
 	data.reportPolicyAffectingFlawDifferences()
 	data.reportNonPolicyAffectingFlawDifferences()
 	data.reportClosedFlawDifferences()
+	data.reportFlawAgeDifferences()
+	data.reportFlawBreakdowns()
+	data.reportOwaspDifferences()
+	data.reportExplanations()
 }
 
 func (data Data) reportFlawStateDifferences() {
@@ -24,8 +32,8 @@ func (data Data) reportFlawStateDifferences() {
 	compareFlawStates(&report, data.ScanAReport, data.ScanBReport)
 
 	if report.Len() > 0 {
-		printTitle("Flaw State Differences")
-		colorPrintf(report.String())
+		data.printTitle("Flaw State Differences")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -35,8 +43,8 @@ func (data Data) reportFlawMitigationDifferences() {
 	compareFlawMitigations(&report, data.ScanAReport, data.ScanBReport)
 
 	if report.Len() > 0 {
-		printTitle("Flaw Mitigation Differences")
-		colorPrintf(report.String())
+		data.printTitle("Flaw Mitigation Differences")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -46,8 +54,8 @@ func (data Data) reportFlawLineNumberChanges() {
 	compareFlawLineNumberChanges(&report, data.ScanAReport, data.ScanBReport)
 
 	if report.Len() > 0 {
-		printTitle("Flaw Line Number Differences")
-		colorPrintf(report.String())
+		data.printTitle("Flaw Line Number Differences")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -58,8 +66,8 @@ func (data Data) reportPolicyAffectingFlawDifferences() {
 	compareFlaws(&report, "B", data.ScanBReport, data.ScanAReport, true, false)
 
 	if report.Len() > 0 {
-		printTitle("Policy Affecting Open Flaw Differences")
-		colorPrintf(report.String())
+		data.printTitle("Policy Affecting Open Flaw Differences")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -70,8 +78,8 @@ func (data Data) reportNonPolicyAffectingFlawDifferences() {
 	compareFlaws(&report, "B", data.ScanBReport, data.ScanAReport, false, false)
 
 	if report.Len() > 0 {
-		printTitle("Non Policy Affecting Open Flaw Differences")
-		colorPrintf(report.String())
+		data.printTitle("Non Policy Affecting Open Flaw Differences")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -82,14 +90,14 @@ func (data Data) reportClosedFlawDifferences() {
 	compareFlaws(&report, "B", data.ScanBReport, data.ScanAReport, false, true)
 
 	if report.Len() > 0 {
-		printTitle("Closed Flaw Differences")
-		colorPrintf(report.String())
+		data.printTitle("Closed Flaw Differences")
+		data.colorPrintf(report.String())
 	}
 }
 
-func getSortedCwes(report DetailedReport) []int {
+func getSortedCwes(flaws []DetailedReportFlaw) []int {
 	var cwes []int
-	for _, thisSideFlaw := range report.Flaws {
+	for _, thisSideFlaw := range flaws {
 		if !isInIntArray(thisSideFlaw.CWE, cwes) {
 			cwes = append(cwes, thisSideFlaw.CWE)
 		}
@@ -99,50 +107,70 @@ func getSortedCwes(report DetailedReport) []int {
 	return cwes
 }
 
-func compareFlaws(report *strings.Builder, side string, thisSideReport, otherSideReport DetailedReport, policyAffecting bool, onlyClosed bool) {
-	for _, cwe := range getSortedCwes(thisSideReport) {
-		var flawsOnlyInThisScan []int
+func isFlawInScope(flaw DetailedReportFlaw, policyAffecting bool, onlyClosed bool) bool {
+	if onlyClosed {
+		return !flaw.isFlawOpen()
+	}
 
-		for _, thisSideFlaw := range thisSideReport.Flaws {
-			if thisSideFlaw.CWE != cwe {
-				continue
-			}
+	if policyAffecting {
+		return flaw.isFlawOpen() && flaw.AffectsPolicyCompliance
+	}
 
-			if onlyClosed {
-				if thisSideFlaw.isFlawOpen() {
-					continue
-				}
-			} else {
-				if policyAffecting && !(thisSideFlaw.isFlawOpen() && thisSideFlaw.AffectsPolicyCompliance) {
-					continue
-				}
+	return flaw.isFlawOpen() && !flaw.AffectsPolicyCompliance
+}
+
+func getFlawsOnlyInThisScan(thisSideReport, otherSideReport DetailedReport, policyAffecting bool, onlyClosed bool) []DetailedReportFlaw {
+	var flawsOnlyInThisScan []DetailedReportFlaw
+
+	for _, thisSideFlaw := range thisSideReport.Flaws {
+		if !isFlawInScope(thisSideFlaw, policyAffecting, onlyClosed) {
+			continue
+		}
+
+		if !otherSideReport.isFlawInReport(thisSideFlaw.MatchId) {
+			flawsOnlyInThisScan = append(flawsOnlyInThisScan, thisSideFlaw)
+		}
+	}
+
+	return flawsOnlyInThisScan
+}
+
+func compareFlaws(report *strings.Builder, side string, thisSideReport, otherSideReport DetailedReport, policyAffecting bool, onlyClosed bool) {
+	flawsOnlyInThisScan := getFlawsOnlyInThisScan(thisSideReport, otherSideReport, policyAffecting, onlyClosed)
 
-				if !policyAffecting && !(thisSideFlaw.isFlawOpen() && !thisSideFlaw.AffectsPolicyCompliance) {
-					continue
+	for _, severity := range getSeveritiesDescending(flawsOnlyInThisScan) {
+		for _, cwe := range getSortedCwes(flawsOnlyInThisScan) {
+			var flawIds []int
+
+			for _, flaw := range flawsOnlyInThisScan {
+				if flaw.Severity == severity && flaw.CWE == cwe {
+					flawIds = append(flawIds, flaw.ID)
 				}
 			}
 
-			if !otherSideReport.isFlawInReport(thisSideFlaw.ID) {
-				flawsOnlyInThisScan = append(flawsOnlyInThisScan, thisSideFlaw.ID)
+			if len(flawIds) > 0 {
+				report.WriteString(fmt.Sprintf("%s: %s %dx %s = %s\n",
+					getFormattedOnlyInSideString(side),
+					getFormattedSeverity(severity),
+					len(flawIds),
+					getFormattedCwe(cwe),
+					getSortedIntArrayAsFormattedString(flawIds)))
 			}
 		}
-
-		if len(flawsOnlyInThisScan) > 0 {
-			report.WriteString(fmt.Sprintf("%s: %dx CWE-%d = %s\n",
-				getFormattedOnlyInSideString(side),
-				len(flawsOnlyInThisScan),
-				cwe,
-				getSortedIntArrayAsFormattedString(flawsOnlyInThisScan)))
-		}
 	}
 }
 
+type flawStateChange struct {
+	severity    int
+	description string
+}
+
 func compareFlawStates(report *strings.Builder, thisSideReport, otherSideReport DetailedReport) {
-	stateChanges := make(map[string][]int)
+	stateChanges := make(map[flawStateChange][]int)
 
 	for _, thisSideFlaw := range thisSideReport.Flaws {
 		for _, otherSideFlaw := range otherSideReport.Flaws {
-			if thisSideFlaw.ID != otherSideFlaw.ID {
+			if thisSideFlaw.MatchId != otherSideFlaw.MatchId {
 				continue
 			}
 
@@ -150,44 +178,53 @@ func compareFlawStates(report *strings.Builder, thisSideReport, otherSideReport
 				continue
 			}
 
-			var stateChange = fmt.Sprintf("%s %-9s => %s %-9s: CWE-%d",
+			var stateChange = fmt.Sprintf("%s %-9s => %s %-9s: %s",
 				getFormattedSideString("A"),
 				thisSideFlaw.RemediationStatus,
 				getFormattedSideString("B"),
 				otherSideFlaw.RemediationStatus,
-				thisSideFlaw.CWE)
+				getFormattedCwe(thisSideFlaw.CWE))
 
-			stateChanges[stateChange] = append(stateChanges[stateChange], thisSideFlaw.ID)
+			key := flawStateChange{thisSideFlaw.Severity, stateChange}
+			stateChanges[key] = append(stateChanges[key], thisSideFlaw.ID)
 
 		}
 	}
 
-	sortedKeys := make([]string, 0, len(stateChanges))
+	sortedKeys := make([]flawStateChange, 0, len(stateChanges))
 	for k := range stateChanges {
 		sortedKeys = append(sortedKeys, k)
 	}
 
-	sort.Strings(sortedKeys)
+	// Highest severity first
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i].severity != sortedKeys[j].severity {
+			return sortedKeys[i].severity > sortedKeys[j].severity
+		}
+
+		return sortedKeys[i].description < sortedKeys[j].description
+	})
 
 	for _, key := range sortedKeys {
 		var flawIds = stateChanges[key]
 
-		var formattedS = strings.Replace(key, "CWE", fmt.Sprintf("%dx CWE", len(flawIds)), 1)
-		report.WriteString(fmt.Sprintf("%s = %s\n", formattedS, getSortedIntArrayAsFormattedString(flawIds)))
+		var formattedS = strings.Replace(key.description, "CWE", fmt.Sprintf("%dx CWE", len(flawIds)), 1)
+		report.WriteString(fmt.Sprintf("%s %s = %s\n", getFormattedSeverity(key.severity), formattedS, getSortedIntArrayAsFormattedString(flawIds)))
 	}
 }
 
 func compareFlawMitigations(report *strings.Builder, thisSideReport, otherSideReport DetailedReport) {
-	for _, thisSideFlaw := range thisSideReport.Flaws {
+	for _, thisSideFlaw := range getFlawsSortedBySeverity(thisSideReport.Flaws) {
 		for _, otherSideFlaw := range otherSideReport.Flaws {
-			if thisSideFlaw.ID != otherSideFlaw.ID {
+			if thisSideFlaw.MatchId != otherSideFlaw.MatchId {
 				continue
 			}
 
 			if thisSideFlaw.MitigationStatus != otherSideFlaw.MitigationStatus {
-				report.WriteString(fmt.Sprintf("%d (CWE-%d): %s: %s, %s: %s\n",
+				report.WriteString(fmt.Sprintf("%s %d (%s): %s: %s, %s: %s\n",
+					getFormattedSeverity(thisSideFlaw.Severity),
 					thisSideFlaw.ID,
-					thisSideFlaw.CWE,
+					getFormattedCwe(thisSideFlaw.CWE),
 					getFormattedSideString("A"),
 					cases.Title(language.English).String(thisSideFlaw.MitigationStatus),
 					getFormattedSideString("B"),
@@ -198,16 +235,18 @@ func compareFlawMitigations(report *strings.Builder, thisSideReport, otherSideRe
 }
 
 func compareFlawLineNumberChanges(report *strings.Builder, thisSideReport, otherSideReport DetailedReport) {
-	for _, thisSideFlaw := range thisSideReport.Flaws {
+	for _, thisSideFlaw := range getFlawsSortedBySeverity(thisSideReport.Flaws) {
 		for _, otherSideFlaw := range otherSideReport.Flaws {
-			if thisSideFlaw.ID != otherSideFlaw.ID {
+			if thisSideFlaw.MatchId != otherSideFlaw.MatchId {
 				continue
 			}
 
-			if thisSideFlaw.LineNumber != otherSideFlaw.LineNumber {
-				report.WriteString(fmt.Sprintf("%d (CWE-%d): %s: %d, %s: %d\n",
+			// Moved flaws are reported separately
+			if thisSideFlaw.LineNumber != otherSideFlaw.LineNumber && !otherSideFlaw.hasMovedFrom(thisSideFlaw) {
+				report.WriteString(fmt.Sprintf("%s %d (%s): %s: %d, %s: %d\n",
+					getFormattedSeverity(thisSideFlaw.Severity),
 					thisSideFlaw.ID,
-					thisSideFlaw.CWE,
+					getFormattedCwe(thisSideFlaw.CWE),
 					getFormattedSideString("A"),
 					thisSideFlaw.LineNumber,
 					getFormattedSideString("B"),
@@ -216,3 +255,22 @@ func compareFlawLineNumberChanges(report *strings.Builder, thisSideReport, other
 		}
 	}
 }
+
+// Returns the flaws open in this scan which are either not present or no longer open in the other scan
+func getFlawsClosedInOtherScan(thisSideReport, otherSideReport DetailedReport) []DetailedReportFlaw {
+	var closedFlaws []DetailedReportFlaw
+
+	for _, thisSideFlaw := range thisSideReport.Flaws {
+		if !thisSideFlaw.isFlawOpen() {
+			continue
+		}
+
+		otherSideFlaw := otherSideReport.getMatchingFlaw(thisSideFlaw)
+
+		if otherSideFlaw.ID == 0 || !otherSideFlaw.isFlawOpen() {
+			closedFlaws = append(closedFlaws, thisSideFlaw)
+		}
+	}
+
+	return closedFlaws
+}
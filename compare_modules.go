@@ -15,8 +15,8 @@ func (data Data) reportTopLevelModuleDifferences() {
 	compareTopLevelSelectedModules(&report, "B", data.ScanBReport.StaticAnalysis.Modules, data.ScanAReport.StaticAnalysis.Modules, data.ScanBPrescanFileList, data.ScanBPrescanModuleList)
 
 	if report.Len() > 0 {
-		printTitle("Differences of Top-Level Modules Selected As An Entry Point For Scanning")
-		colorPrintf(report.String())
+		data.printTitle("Differences of Top-Level Modules Selected As An Entry Point For Scanning")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -92,12 +92,12 @@ func (data Data) reportNotSelectedModuleDifferences() {
 
 	if report.Len() > 0 {
 		if strings.Contains(report.String(), "files extracted from") {
-			printTitle("Differences of Top-Level Modules Which May or May Not Have Been Selected")
+			data.printTitle("Differences of Top-Level Modules Which May or May Not Have Been Selected")
 		} else {
-			printTitle("Differences of Top-Level Modules Not Selected As An Entry Point (And Not Scanned) - Unselected Potential First Party Components")
+			data.printTitle("Differences of Top-Level Modules Not Selected As An Entry Point (And Not Scanned) - Unselected Potential First Party Components")
 		}
 
-		colorPrintf(report.String())
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -108,8 +108,8 @@ func (data Data) reportDependencyModuleDifferences() {
 	compareTopLevelNotSelectedModules(&report, "B", data.ScanBPrescanModuleList, data.ScanAPrescanModuleList, data.ScanBReport.StaticAnalysis.Modules, true)
 
 	if report.Len() > 0 {
-		printTitle("Differences of Dependency Modules Not Selected As An Entry Point")
-		colorPrintf(report.String())
+		data.printTitle("Differences of Dependency Modules Not Selected As An Entry Point")
+		data.colorPrintf(report.String())
 	}
 }
 
@@ -167,7 +167,7 @@ func compareTopLevelNotSelectedModules(report *strings.Builder, side string, pre
 	}
 }
 
-func reportDuplicateFiles(side string, prescanFileList PrescanFileList) {
+func (data Data) reportDuplicateFiles(side string, prescanFileList PrescanFileList) {
 	var report strings.Builder
 	var processedFiles []string
 
@@ -200,9 +200,9 @@ func reportDuplicateFiles(side string, prescanFileList PrescanFileList) {
 	}
 
 	if report.Len() > 0 {
-		colorPrintf(getFormattedSideStringWithMessage(side, fmt.Sprintf("\nDuplicate Files Within Scan %s\n", side)))
-		fmt.Print("=============================\n")
-		color.HiYellow(report.String())
+		data.colorPrintf(getFormattedSideStringWithMessage(side, fmt.Sprintf("\nDuplicate Files Within Scan %s\n", side)))
+		data.colorPrintf("=============================\n")
+		data.colorPrintf(color.HiYellowString(report.String()))
 	}
 }
 
@@ -229,8 +229,14 @@ func getNonDuplicatedFileNames(fileList PrescanFileList) []string {
 	return nonDuplicatedFiles
 }
 
-func (data Data) reportModuleDifferences() {
-	var report strings.Builder
+type PrescanFileChange struct {
+	Name     string
+	ScanAMD5 string
+	ScanBMD5 string
+}
+
+func (data Data) getChangedFiles() []PrescanFileChange {
+	var changedFiles []PrescanFileChange
 
 	var scanANonDuplicatedFiles = getNonDuplicatedFileNames(data.ScanAPrescanFileList)
 	var scanBNonDuplicatedFiles = getNonDuplicatedFileNames(data.ScanBPrescanFileList)
@@ -247,20 +253,47 @@ func (data Data) reportModuleDifferences() {
 		for _, otherFile := range data.ScanBPrescanFileList.Files {
 			if thisFile.Name == otherFile.Name {
 				if thisFile.MD5 != otherFile.MD5 {
-					report.WriteString(
-						fmt.Sprintf("\"%s\" %s: MD5 = %s, %s: MD5 = %s \n",
-							thisFile.Name,
-							getFormattedSideString("A"),
-							thisFile.MD5,
-							getFormattedSideString("B"),
-							otherFile.MD5))
+					changedFiles = append(changedFiles, PrescanFileChange{thisFile.Name, thisFile.MD5, otherFile.MD5})
 				}
 			}
 		}
 	}
 
+	return changedFiles
+}
+
+func (data Data) reportModuleDifferences() {
+	var report strings.Builder
+
+	for _, changedFile := range data.getChangedFiles() {
+		report.WriteString(
+			fmt.Sprintf("\"%s\" %s: MD5 = %s, %s: MD5 = %s \n",
+				changedFile.Name,
+				getFormattedSideString("A"),
+				changedFile.ScanAMD5,
+				getFormattedSideString("B"),
+				changedFile.ScanBMD5))
+	}
+
 	if report.Len() > 0 {
-		printTitle("Module Differences (Ignoring any duplicates)")
-		colorPrintf(report.String())
+		data.printTitle("Module Differences (Ignoring any duplicates)")
+		data.colorPrintf(report.String())
 	}
 }
+
+func getSelectedModuleNamesOnlyInThisScan(modulesInThisSideReport, modulesInTheOtherSideReport []DetailedReportModule) []string {
+	moduleNames := []string{}
+
+	for _, module := range modulesInThisSideReport {
+		if !module.isModuleNameInDetailedReportModuleArray(modulesInTheOtherSideReport) {
+			moduleNames = append(moduleNames, module.Name)
+		}
+	}
+
+	return moduleNames
+}
+
+func getSelectedModuleDriftCount(modulesInThisSideReport, modulesInTheOtherSideReport []DetailedReportModule) int {
+	return len(getSelectedModuleNamesOnlyInThisScan(modulesInThisSideReport, modulesInTheOtherSideReport)) +
+		len(getSelectedModuleNamesOnlyInThisScan(modulesInTheOtherSideReport, modulesInThisSideReport))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -47,6 +48,10 @@ func getCredentials(id, key string, profile string) (string, string) {
 	id = ""
 	key = ""
 
+	if len(profile) == 0 {
+		profile = "default"
+	}
+
 	// Then try environment variables
 	id = os.Getenv("VERACODE_API_KEY_ID")
 	key = os.Getenv("VERACODE_API_KEY_SECRET")
@@ -73,10 +78,11 @@ func getCredentials(id, key string, profile string) (string, string) {
 		os.Exit(1)
 	}
 
-	id = ""
-	key = ""
-
 	// Finally look for a Veracode credentials file
+	return getCredentialsFromProfile(profile)
+}
+
+func getCredentialsFilePath() string {
 	homePath, err := os.UserHomeDir()
 
 	if err != nil {
@@ -84,45 +90,73 @@ func getCredentials(id, key string, profile string) (string, string) {
 		os.Exit(1)
 	}
 
-	var credentialsFilePath = filepath.Join(homePath, ".veracode", "credentials")
+	return filepath.Join(homePath, ".veracode", "credentials")
+}
 
-	if _, err := os.Stat(credentialsFilePath); errors.Is(err, os.ErrNotExist) {
-		color.HiRed("Error: Could not resolve any API credentials. Use either -vid and -vkey command line arguments, set VERACODE_API_KEY_ID and VERACODE_API_KEY_SECRET environment variables or create a Veracode credentials file. See https://docs.veracode.com/r/c_configure_api_cred_file")
+// Profiles can be mapped to accounts with an optional "account_id" key, which may contain a comma-separated list of account IDs
+func getProfileForAccountId(accountId int) string {
+	cfg, err := ini.Load(getCredentialsFilePath())
+
+	if err != nil {
+		return ""
+	}
+
+	for _, section := range cfg.Sections() {
+		for _, mappedAccountId := range strings.Split(section.Key("account_id").String(), ",") {
+			if strings.TrimSpace(mappedAccountId) == strconv.Itoa(accountId) {
+				return section.Name()
+			}
+		}
+	}
+
+	return ""
+}
+
+func getCredentialsFromProfile(profile string) (string, string) {
+	id, key, err := tryGetCredentialsFromProfile(profile)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
 		os.Exit(1)
 	}
 
+	return id, key
+}
+
+// Returns an error rather than exiting, so a profile mapped by account ID can fall back to the default credentials
+func tryGetCredentialsFromProfile(profile string) (string, string, error) {
+	var credentialsFilePath = getCredentialsFilePath()
+
+	if _, err := os.Stat(credentialsFilePath); errors.Is(err, os.ErrNotExist) {
+		return "", "", errors.New("Could not resolve any API credentials. Use either -vid and -vkey command line arguments, set VERACODE_API_KEY_ID and VERACODE_API_KEY_SECRET environment variables or create a Veracode credentials file. See https://docs.veracode.com/r/c_configure_api_cred_file")
+	}
+
 	cfg, err := ini.Load(credentialsFilePath)
 	if err != nil {
-		color.HiRed("Error: Could not open the Veracode credentials file. See https://docs.veracode.com/r/c_configure_api_cred_file")
-		os.Exit(1)
+		return "", "", errors.New("Could not open the Veracode credentials file. See https://docs.veracode.com/r/c_configure_api_cred_file")
 	}
 
 	if !cfg.HasSection(profile) {
-		color.HiRed(fmt.Sprintf("Error: Could not find the profile [%s] within the Veracode credentials file. See https://docs.veracode.com/r/c_httpie_tool", profile))
-		os.Exit(1)
+		return "", "", fmt.Errorf("Could not find the profile [%s] within the Veracode credentials file. See https://docs.veracode.com/r/c_httpie_tool", profile)
 	}
 
-	id = cfg.Section(profile).Key("veracode_api_key_id").String()
-	key = cfg.Section(profile).Key("veracode_api_key_secret").String()
+	id := cfg.Section(profile).Key("veracode_api_key_id").String()
+	key := cfg.Section(profile).Key("veracode_api_key_secret").String()
 
 	if len(id) > 0 && len(key) > 0 {
 		id = formatCredential(id)
 		key = formatCredential(key)
 
 		if len(id) != 32 {
-			color.HiRed("Error: Invalid value for veracode_api_key_id in file \"%s\"", credentialsFilePath)
-			os.Exit(1)
+			return "", "", fmt.Errorf("Invalid value for veracode_api_key_id in file \"%s\"", credentialsFilePath)
 		}
 
 		if len(key) != 128 {
-			color.HiRed("Error: Invalid value for veracode_api_key_secret in file \"%s\"", credentialsFilePath)
-			os.Exit(1)
+			return "", "", fmt.Errorf("Invalid value for veracode_api_key_secret in file \"%s\"", credentialsFilePath)
 		}
 
-		return id, key
+		return id, key, nil
 	}
 
-	color.HiRed("Error: Could not parse credentials from the Veracode credentials file. See https://docs.veracode.com/r/c_configure_api_cred_file")
-	os.Exit(1)
-	return "", ""
+	return "", "", errors.New("Could not parse credentials from the Veracode credentials file. See https://docs.veracode.com/r/c_configure_api_cred_file")
 }
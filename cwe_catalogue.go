@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type CweCatalogueEntry struct {
+	Name        string
+	Description string
+	Owasp       string // OWASP Top 10 2021 category ID, if any
+	Top25Rank   int    // CWE Top 25 2023 rank, 0 if not in the Top 25
+}
+
+var owaspTop10Categories = map[string]string{
+	"A01": "Broken Access Control",
+	"A02": "Cryptographic Failures",
+	"A03": "Injection",
+	"A04": "Insecure Design",
+	"A05": "Security Misconfiguration",
+	"A06": "Vulnerable and Outdated Components",
+	"A07": "Identification and Authentication Failures",
+	"A08": "Software and Data Integrity Failures",
+	"A09": "Security Logging and Monitoring Failures",
+	"A10": "Server-Side Request Forgery",
+}
+
+// The CWEs most commonly reported by Veracode, so the output can be read without looking each one up
+var cweCatalogue = map[int]CweCatalogueEntry{
+	15:   {"External Control of System or Configuration Setting", "A system setting can be changed by user input", "A05", 0},
+	20:   {"Improper Input Validation", "Input is not validated before being used", "A03", 6},
+	22:   {"Path Traversal", "A file path built from input can escape the intended directory", "A01", 8},
+	73:   {"External Control of File Name or Path", "A file name or path is controlled by user input", "A04", 0},
+	77:   {"Command Injection", "Input is used to build a command without neutralisation", "A03", 16},
+	78:   {"OS Command Injection", "Input is used to build an operating system command", "A03", 5},
+	79:   {"Cross-site Scripting", "Input is written to a web page without encoding", "A03", 2},
+	80:   {"Basic Cross-site Scripting", "Script tags from input are written to a web page", "A03", 0},
+	83:   {"Cross-site Scripting in Attributes", "Input is written to an HTML attribute without encoding", "A03", 0},
+	89:   {"SQL Injection", "Input is used to build a SQL query", "A03", 3},
+	90:   {"LDAP Injection", "Input is used to build an LDAP query", "A03", 0},
+	91:   {"XML Injection", "Input is used to build an XML document", "A03", 0},
+	93:   {"CRLF Injection", "Line breaks from input are written to a header or log", "A03", 0},
+	94:   {"Code Injection", "Input is used to generate code which is then executed", "A03", 23},
+	95:   {"Eval Injection", "Input is passed to a dynamic evaluation function", "A03", 0},
+	99:   {"Resource Injection", "Input controls the identifier of a resource", "A03", 0},
+	113:  {"HTTP Response Splitting", "Line breaks from input are written to an HTTP header", "A03", 0},
+	117:  {"Improper Output Neutralization for Logs", "Input is written to a log without neutralisation, allowing forged entries", "A09", 0},
+	119:  {"Improper Restriction of Operations within the Bounds of a Memory Buffer", "Memory is read or written outside of a buffer", "", 17},
+	120:  {"Classic Buffer Overflow", "Input is copied into a buffer without checking its size", "", 0},
+	121:  {"Stack-based Buffer Overflow", "A buffer on the stack can be overwritten", "", 0},
+	122:  {"Heap-based Buffer Overflow", "A buffer on the heap can be overwritten", "", 0},
+	125:  {"Out-of-bounds Read", "Data is read from outside of a buffer", "", 7},
+	126:  {"Buffer Over-read", "Data is read past the end of a buffer", "", 0},
+	129:  {"Improper Validation of Array Index", "An array index from input is not checked", "", 0},
+	134:  {"Use of Externally-Controlled Format String", "Input is used as a format string", "", 0},
+	170:  {"Improper Null Termination", "A string may not be null terminated", "", 0},
+	190:  {"Integer Overflow or Wraparound", "An integer calculation can exceed its maximum value", "", 14},
+	191:  {"Integer Underflow", "An integer calculation can go below its minimum value", "", 0},
+	200:  {"Exposure of Sensitive Information", "Sensitive information is exposed to an unauthorised actor", "A01", 0},
+	201:  {"Insertion of Sensitive Information Into Sent Data", "Sensitive information is included in data sent to another actor", "A01", 0},
+	209:  {"Generation of Error Message Containing Sensitive Information", "Error messages reveal details about the system", "A04", 0},
+	215:  {"Insertion of Sensitive Information Into Debugging Code", "Debug output reveals sensitive information", "", 0},
+	226:  {"Sensitive Information in Resource Not Removed Before Reuse", "Memory or a resource is reused without being cleared", "", 0},
+	244:  {"Heap Inspection", "Sensitive data is left in memory after use", "", 0},
+	252:  {"Unchecked Return Value", "The return value of a function is not checked", "", 0},
+	259:  {"Use of Hard-coded Password", "A password is embedded in the code", "A07", 0},
+	260:  {"Password in Configuration File", "A password is stored in a configuration file", "A05", 0},
+	261:  {"Weak Encoding for Password", "A password is obscured with a weak encoding", "A02", 0},
+	269:  {"Improper Privilege Management", "Privileges are not assigned, modified or checked correctly", "A04", 22},
+	276:  {"Incorrect Default Permissions", "Files or resources are created with permissive defaults", "A01", 25},
+	284:  {"Improper Access Control", "Access to a resource is not restricted correctly", "A01", 0},
+	285:  {"Improper Authorization", "An authorisation check is missing or incorrect", "A01", 0},
+	287:  {"Improper Authentication", "An identity claim is not proven correctly", "A07", 13},
+	295:  {"Improper Certificate Validation", "TLS certificates are not validated", "A07", 0},
+	297:  {"Improper Validation of Certificate with Host Mismatch", "The TLS certificate host name is not checked", "A07", 0},
+	306:  {"Missing Authentication for Critical Function", "A critical function does not require authentication", "A07", 20},
+	311:  {"Missing Encryption of Sensitive Data", "Sensitive data is stored or sent without encryption", "A04", 0},
+	312:  {"Cleartext Storage of Sensitive Information", "Sensitive data is stored unencrypted", "A04", 0},
+	313:  {"Cleartext Storage in a File or on Disk", "Sensitive data is written to disk unencrypted", "A04", 0},
+	316:  {"Cleartext Storage of Sensitive Information in Memory", "Sensitive data is held in memory unencrypted", "A04", 0},
+	327:  {"Use of a Broken or Risky Cryptographic Algorithm", "A weak cryptographic algorithm is used", "A02", 0},
+	329:  {"Generation of Predictable IV with CBC Mode", "The initialisation vector is not random", "A02", 0},
+	330:  {"Use of Insufficiently Random Values", "A predictable random number generator is used", "A02", 0},
+	331:  {"Insufficient Entropy", "Random values do not have enough entropy", "A02", 0},
+	336:  {"Same Seed in Pseudo-Random Number Generator", "A random number generator is seeded with a constant", "A02", 0},
+	352:  {"Cross-Site Request Forgery", "Requests are not verified as intentionally sent by the user", "A01", 9},
+	359:  {"Exposure of Private Personal Information", "Personal information is exposed to an unauthorised actor", "A01", 0},
+	362:  {"Race Condition", "Shared resources are used concurrently without synchronisation", "", 21},
+	378:  {"Creation of Temporary File With Insecure Permissions", "A temporary file can be read or modified by others", "", 0},
+	382:  {"J2EE Bad Practices: Use of System.exit()", "A web application can be shut down from within", "", 0},
+	384:  {"Session Fixation", "The session ID is not renewed on authentication", "A07", 0},
+	391:  {"Unchecked Error Condition", "An error is caught and ignored", "", 0},
+	400:  {"Uncontrolled Resource Consumption", "Resource use is not limited", "", 0},
+	401:  {"Missing Release of Memory after Effective Lifetime", "Memory is not freed when no longer needed", "", 0},
+	404:  {"Improper Resource Shutdown or Release", "A resource is not released when no longer needed", "", 0},
+	413:  {"Improper Resource Locking", "A resource is not locked before use", "", 0},
+	415:  {"Double Free", "Memory is freed twice", "", 0},
+	416:  {"Use After Free", "Memory is used after it has been freed", "", 4},
+	434:  {"Unrestricted Upload of File with Dangerous Type", "Uploaded files are not restricted by type", "A04", 10},
+	457:  {"Use of Uninitialized Variable", "A variable is used before it is initialised", "", 0},
+	470:  {"Unsafe Reflection", "Input selects the class or code to load", "A03", 0},
+	476:  {"NULL Pointer Dereference", "A pointer that may be null is dereferenced", "", 12},
+	489:  {"Active Debug Code", "Debug code has been left in the application", "", 0},
+	497:  {"Exposure of System Data", "System information is exposed to an unauthorised actor", "A01", 0},
+	501:  {"Trust Boundary Violation", "Trusted and untrusted data are mixed in the same structure", "A04", 0},
+	502:  {"Deserialization of Untrusted Data", "Untrusted data is deserialised", "A08", 15},
+	521:  {"Weak Password Requirements", "Weak passwords are allowed", "A07", 0},
+	532:  {"Insertion of Sensitive Information into Log File", "Sensitive information is written to a log", "A09", 0},
+	564:  {"SQL Injection: Hibernate", "Input is used to build a Hibernate query", "A03", 0},
+	566:  {"Authorization Bypass Through User-Controlled SQL Primary Key", "Input selects a database record without an access check", "A01", 0},
+	597:  {"Use of Wrong Operator in String Comparison", "Strings are compared by reference instead of value", "", 0},
+	601:  {"Open Redirect", "Input controls the destination of a redirect", "A01", 0},
+	611:  {"XML External Entity Reference", "XML parsing resolves external entities", "A05", 0},
+	614:  {"Sensitive Cookie Without 'Secure' Attribute", "A cookie can be sent over an unencrypted connection", "A05", 0},
+	639:  {"Authorization Bypass Through User-Controlled Key", "Input selects a record without an access check", "A01", 0},
+	643:  {"XPath Injection", "Input is used to build an XPath query", "A03", 0},
+	676:  {"Use of Potentially Dangerous Function", "A function which is unsafe if used incorrectly is called", "", 0},
+	690:  {"Unchecked Return Value to NULL Pointer Dereference", "A return value that may be null is used without a check", "", 0},
+	732:  {"Incorrect Permission Assignment for Critical Resource", "A critical resource has permissive access rights", "", 0},
+	749:  {"Exposed Dangerous Method or Function", "A dangerous method can be called externally", "", 0},
+	757:  {"Selection of Less-Secure Algorithm During Negotiation", "A weaker algorithm can be negotiated", "A02", 0},
+	772:  {"Missing Release of Resource after Effective Lifetime", "A resource is not released when no longer needed", "", 0},
+	776:  {"XML Entity Expansion", "Recursive XML entities are expanded", "A05", 0},
+	787:  {"Out-of-bounds Write", "Data is written outside of a buffer", "", 1},
+	798:  {"Use of Hard-coded Credentials", "Credentials are embedded in the code", "A07", 18},
+	829:  {"Inclusion of Functionality from Untrusted Control Sphere", "Code is included from an untrusted source", "A08", 0},
+	862:  {"Missing Authorization", "An authorisation check is missing", "A01", 11},
+	863:  {"Incorrect Authorization", "An authorisation check is incorrect", "A01", 24},
+	915:  {"Mass Assignment", "Input can modify object attributes which should not be modifiable", "A08", 0},
+	916:  {"Use of Password Hash With Insufficient Computational Effort", "Passwords are hashed with a fast algorithm", "A02", 0},
+	918:  {"Server-Side Request Forgery", "Input controls the destination of a server-side request", "A10", 19},
+	926:  {"Improper Export of Android Application Components", "An Android component is exported without restriction", "", 0},
+	927:  {"Use of Implicit Intent for Sensitive Communication", "Sensitive data is sent with an implicit Android intent", "A04", 0},
+	1004: {"Sensitive Cookie Without 'HttpOnly' Flag", "A cookie can be read by scripts", "A05", 0},
+	1021: {"Improper Restriction of Rendered UI Layers", "The page can be framed, allowing clickjacking", "A04", 0},
+}
+
+// Returns e.g. "CWE-89 SQL Injection", or just "CWE-89" if the CWE is not in the catalogue
+func getFormattedCwe(cwe int) string {
+	if entry, found := cweCatalogue[cwe]; found {
+		return fmt.Sprintf("CWE-%d %s", cwe, entry.Name)
+	}
+
+	return fmt.Sprintf("CWE-%d", cwe)
+}
+
+func getOwaspCategory(cwe int) string {
+	if entry, found := cweCatalogue[cwe]; found && len(entry.Owasp) > 0 {
+		return fmt.Sprintf("%s %s", entry.Owasp, owaspTop10Categories[entry.Owasp])
+	}
+
+	return "Not in the OWASP Top 10"
+}
+
+func getCweCatalogueDetails(cwe int) string {
+	entry, found := cweCatalogue[cwe]
+
+	if !found {
+		return ""
+	}
+
+	var mappings []string
+
+	if len(entry.Owasp) > 0 {
+		mappings = append(mappings, fmt.Sprintf("OWASP %s", getOwaspCategory(cwe)))
+	}
+
+	if entry.Top25Rank > 0 {
+		mappings = append(mappings, fmt.Sprintf("CWE Top 25 #%d", entry.Top25Rank))
+	}
+
+	if len(mappings) == 0 {
+		return entry.Description
+	}
+
+	return fmt.Sprintf("%s (%s)", entry.Description, strings.Join(mappings, ", "))
+}
+
+func (data Data) reportOwaspDifferences() {
+	scanAOnlyCounts := make(map[string]int)
+	scanBOnlyCounts := make(map[string]int)
+
+	for _, flaw := range getFlawsOnlyInThisScan(data.ScanAReport, data.ScanBReport, true, false) {
+		scanAOnlyCounts[getOwaspCategory(flaw.CWE)]++
+	}
+
+	for _, flaw := range getFlawsOnlyInThisScan(data.ScanAReport, data.ScanBReport, false, false) {
+		scanAOnlyCounts[getOwaspCategory(flaw.CWE)]++
+	}
+
+	for _, flaw := range getFlawsOnlyInThisScan(data.ScanBReport, data.ScanAReport, true, false) {
+		scanBOnlyCounts[getOwaspCategory(flaw.CWE)]++
+	}
+
+	for _, flaw := range getFlawsOnlyInThisScan(data.ScanBReport, data.ScanAReport, false, false) {
+		scanBOnlyCounts[getOwaspCategory(flaw.CWE)]++
+	}
+
+	var categories []string
+
+	for category := range scanAOnlyCounts {
+		categories = append(categories, category)
+	}
+
+	for category := range scanBOnlyCounts {
+		if _, found := scanAOnlyCounts[category]; !found {
+			categories = append(categories, category)
+		}
+	}
+
+	if len(categories) == 0 {
+		return
+	}
+
+	// "A01" to "A10" sort in order, leaving "Not in the OWASP Top 10" last
+	sort.Strings(categories)
+
+	var report strings.Builder
+
+	for _, category := range categories {
+		report.WriteString(fmt.Sprintf("%-45s %s = %d, %s = %d\n",
+			category+":",
+			getFormattedOnlyInSideString("A"),
+			scanAOnlyCounts[category],
+			getFormattedOnlyInSideString("B"),
+			scanBOnlyCounts[category]))
+	}
+
+	data.printTitle("Open Flaw Differences By OWASP Top 10 Category")
+	data.colorPrintf(report.String())
+}
@@ -1,60 +1,120 @@
 package main
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"sync"
+
+	"github.com/fatih/color"
 )
 
 type Data struct {
+	ScanARegion            string
+	ScanBRegion            string
 	ScanAReport            DetailedReport
 	ScanBReport            DetailedReport
 	ScanAPrescanFileList   PrescanFileList
 	ScanBPrescanFileList   PrescanFileList
 	ScanAPrescanModuleList PrescanModuleList
 	ScanBPrescanModuleList PrescanModuleList
+	MatchedByFingerprint   bool
+	FlawFilter             FlawFilter
+	RiskModel              RiskModel
+	CodeOwners             CodeOwners
+	PathRules              PathRules
+	ScanACallStacks        map[int]CallStacks // Keyed by flaw ID, only fetched when requested
+	ScanBCallStacks        map[int]CallStacks
+	CallStacksSkipped      []int // Scan A flaw IDs whose call stacks could not be fetched
+
+	// Uploaded files which only share a name once the path rules have been applied, keyed by that name
+	ScanAPathRuleCollisions map[string][]string
+	ScanBPathRuleCollisions map[string][]string
+
+	// Reports are written to the terminal unless set, e.g. to a file for the "batch" action
+	Output io.Writer
+}
+
+func getData(scanAApi, scanBApi API, scanABuildId, scanBBuildId int, matchMode string, pathRules PathRules) Data {
+	data, err := tryGetData(scanAApi, scanBApi, scanABuildId, scanBBuildId, matchMode, pathRules)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
+		os.Exit(1)
+	}
+
+	return data
 }
 
-func (api API) getData(scanAAppId, scanABuildId, scanBAppId, scanBBuildId int) Data {
-	var data = Data{}
+// Returns the first error from any of the API calls rather than exiting, so the "batch" action can carry on
+func tryGetData(scanAApi, scanBApi API, scanABuildId, scanBBuildId int, matchMode string, pathRules PathRules) (Data, error) {
+	var data = Data{ScanARegion: scanAApi.region, ScanBRegion: scanBApi.region}
+	var errs [4]error
 
 	var wg sync.WaitGroup
 	wg.Add(2)
 
 	go func() {
 		defer wg.Done()
-		data.ScanAReport = api.getDetailedReport(scanABuildId)
+		data.ScanAReport, errs[0] = scanAApi.tryGetDetailedReport(scanABuildId)
 	}()
 
 	go func() {
 		defer wg.Done()
-		data.ScanBReport = api.getDetailedReport(scanBBuildId)
+		data.ScanBReport, errs[1] = scanBApi.tryGetDetailedReport(scanBBuildId)
 	}()
 
 	wg.Wait()
+
+	if err := getFirstError(errs[:2]); err != nil {
+		return Data{}, err
+	}
+
 	wg.Add(4)
 
 	// We can't rely on the passed-in app IDs as they may not be present if not using a URL, so get the app ID from the detailed report
 
 	go func() {
 		defer wg.Done()
-		data.ScanAPrescanFileList = api.getPrescanFileList(data.ScanAReport.AppId, scanABuildId)
+		data.ScanAPrescanFileList, errs[0] = scanAApi.tryGetPrescanFileList(data.ScanAReport.AppId, scanABuildId)
 	}()
 
 	go func() {
 		defer wg.Done()
-		data.ScanBPrescanFileList = api.getPrescanFileList(data.ScanBReport.AppId, scanBBuildId)
+		data.ScanBPrescanFileList, errs[1] = scanBApi.tryGetPrescanFileList(data.ScanBReport.AppId, scanBBuildId)
 	}()
 
 	go func() {
 		defer wg.Done()
-		data.ScanAPrescanModuleList = api.getPrescanModuleList(data.ScanAReport.AppId, scanABuildId)
+		data.ScanAPrescanModuleList, errs[2] = scanAApi.tryGetPrescanModuleList(data.ScanAReport.AppId, scanABuildId)
 	}()
 
 	go func() {
 		defer wg.Done()
-		data.ScanBPrescanModuleList = api.getPrescanModuleList(data.ScanBReport.AppId, scanBBuildId)
+		data.ScanBPrescanModuleList, errs[3] = scanBApi.tryGetPrescanModuleList(data.ScanBReport.AppId, scanBBuildId)
 	}()
 
 	wg.Wait()
 
-	return data
+	if err := getFirstError(errs[:]); err != nil {
+		return Data{}, err
+	}
+
+	data.applyPathRules(pathRules)
+	data.matchFlaws(matchMode)
+	data.matchRelocatedFlaws()
+	data.matchDynamicFlaws()
+	data.matchManualFlaws()
+
+	return data, nil
+}
+
+func getFirstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -13,22 +13,31 @@ import (
 )
 
 type DetailedReport struct {
-	XMLName              xml.Name                     `xml:"detailedreport"`
-	AccountId            int                          `xml:"account_id,attr"`
-	AppId                int                          `xml:"app_id,attr"`
-	AppName              string                       `xml:"app_name,attr"`
-	SandboxId            int                          `xml:"sandbox_id,attr"`
-	SandboxName          string                       `xml:"sandbox_name,attr"`
-	BuildId              int                          `xml:"build_id,attr"`
-	AnalysisId           int                          `xml:"analysis_id,attr"`
-	StaticAnalysisUnitId int                          `xml:"static_analysis_unit_id,attr"`
-	TotalFlaws           int                          `xml:"total_flaws,attr"`
-	UnmitigatedFlaws     int                          `xml:"flaws_not_mitigated,attr"`
-	StaticAnalysis       DetailedReportStaticAnalysis `xml:"static-analysis"`
-	Flaws                []DetailedReportFlaw         `xml:"severity>category>cwe>staticflaws>flaw"`
-	SubmittedDate        time.Time
-	PublishedDate        time.Time
-	Duration             time.Duration
+	XMLName                xml.Name                     `xml:"detailedreport"`
+	AccountId              int                          `xml:"account_id,attr"`
+	AppId                  int                          `xml:"app_id,attr"`
+	AppName                string                       `xml:"app_name,attr"`
+	SandboxId              int                          `xml:"sandbox_id,attr"`
+	SandboxName            string                       `xml:"sandbox_name,attr"`
+	BuildId                int                          `xml:"build_id,attr"`
+	AnalysisId             int                          `xml:"analysis_id,attr"`
+	StaticAnalysisUnitId   int                          `xml:"static_analysis_unit_id,attr"`
+	TotalFlaws             int                          `xml:"total_flaws,attr"`
+	UnmitigatedFlaws       int                          `xml:"flaws_not_mitigated,attr"`
+	PolicyName             string                       `xml:"policy_name,attr"`
+	PolicyVersion          int                          `xml:"policy_version,attr"`
+	PolicyComplianceStatus string                       `xml:"policy_compliance_status,attr"`
+	PolicyRulesStatus      string                       `xml:"policy_rules_status,attr"`
+	StaticAnalysis         DetailedReportStaticAnalysis `xml:"static-analysis"`
+	DynamicAnalysis        DetailedReportDAST           `xml:"dynamic-analysis"`
+	Severities             []DetailedReportSeverity     `xml:"severity"`
+	SCA                    DetailedReportSCA            `xml:"software_composition_analysis"`
+	Flaws                  []DetailedReportFlaw         `xml:"-"`
+	DynamicFlaws           []DetailedReportFlaw         `xml:"-"`
+	ManualFlaws            []DetailedReportFlaw         `xml:"-"`
+	SubmittedDate          time.Time
+	PublishedDate          time.Time
+	Duration               time.Duration
 }
 
 type DetailedReportStaticAnalysis struct {
@@ -50,37 +59,118 @@ type DetailedReportModule struct {
 	Architecture string   `xml:"architecture,attr"`
 }
 
+type DetailedReportSeverity struct {
+	XMLName    xml.Name                 `xml:"severity"`
+	Level      int                      `xml:"level,attr"`
+	Categories []DetailedReportCategory `xml:"category"`
+}
+
+type DetailedReportCategory struct {
+	XMLName      xml.Name             `xml:"category"`
+	Name         string               `xml:"categoryname,attr"`
+	Flaws        []DetailedReportFlaw `xml:"cwe>staticflaws>flaw"`
+	DynamicFlaws []DetailedReportFlaw `xml:"cwe>dynamicflaws>flaw"`
+	ManualFlaws  []DetailedReportFlaw `xml:"cwe>manualflaws>flaw"`
+}
+
 type DetailedReportFlaw struct {
 	XMLName                 xml.Name `xml:"flaw"`
 	ID                      int      `xml:"issueid,attr"`
 	CWE                     int      `xml:"cweid,attr"`
+	Severity                int      `xml:"-"`
+	CategoryName            string   `xml:"-"`
 	AffectsPolicyCompliance bool     `xml:"affects_policy_compliance,attr"`
 	Module                  string   `xml:"module,attr"`
 	RemediationStatus       string   `xml:"remediation_status,attr"`
 	MitigationStatus        string   `xml:"mitigation_status,attr"`
 	SourceFile              string   `xml:"source_file,attr"`
 	LineNumber              int      `xml:"line,attr"`
+	RemediationEffort       int      `xml:"remediationeffort,attr"`
+	ExploitLevel            string   `xml:"exploitLevel,attr"`
+	SourceFilePath          string   `xml:"sourcefilepath,attr"`
+	FunctionPrototype       string   `xml:"functionprototype,attr"`
+	FunctionLocation        int      `xml:"functionrelativelocation,attr"`
 	ProcedureHash           string   `xml:"procedure_hash,attr"`
 	PrototypeHash           string   `xml:"prototype_hash,attr"`
 	StatementHash           string   `xml:"statement_hash,attr"`
+	URL                     string   `xml:"url,attr"`
+	VulnerableParameter     string   `xml:"vuln_parameter,attr"`
+	MatchId                 int      `xml:"-"` // Used to pair flaws between scans, the issue ID unless matched by fingerprint
+	MatchConfidence         string   `xml:"-"` // Only set when matched by fingerprint
+	Relocated               bool     `xml:"-"` // Set when paired with a flaw at a different location by hashes or function prototype
+
+	// The mitigation proposals, approvals, rejections and comments made against this flaw
+	Annotations []DetailedReportAnnotation `xml:"annotations>annotation"`
+
+	DateFirstOccurrence   string    `xml:"date_first_occurrence,attr"`
+	GracePeriodExpires    string    `xml:"grace_period_expires,attr"`
+	FirstFoundDate        time.Time `xml:"-"` // Zero if not known
+	GracePeriodExpiryDate time.Time `xml:"-"` // Zero if the flaw has no grace period
+}
+
+type DetailedReportAnnotation struct {
+	XMLName     xml.Name `xml:"annotation"`
+	Action      string   `xml:"action,attr"`
+	Description string   `xml:"description,attr"`
+	User        string   `xml:"user,attr"`
+	Date        string   `xml:"date,attr"`
 }
 
 func (api API) getDetailedReport(buildId int) DetailedReport {
+	report, err := api.tryGetDetailedReport(buildId)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
+		os.Exit(1)
+	}
+
+	return report
+}
+
+func (api API) tryGetDetailedReport(buildId int) (DetailedReport, error) {
 	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/detailedreport.do?build_id=%d", buildId)
-	response := api.makeApiRequest(url, http.MethodGet)
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return DetailedReport{}, err
+	}
 
 	if strings.Contains(string(response[:]), "<error>A valid app could not be found for build_id") {
-		color.HiRed(fmt.Sprintf("Error: The build id %d is not recognised by the Veracode Platform. Has the scan been started?", buildId))
-		os.Exit(1)
+		return DetailedReport{}, fmt.Errorf("The build id %d is not recognised by the Veracode Platform. Has the scan been started?", buildId)
 	}
 
 	if strings.Contains(string(response[:]), "<error>No report available.</error>") {
-		color.HiRed(fmt.Sprintf("Error: There was no detailed report for build id %d. Has the scan finished?", buildId))
-		os.Exit(1)
+		return DetailedReport{}, fmt.Errorf("There was no detailed report for build id %d. Has the scan finished?", buildId)
 	}
 
 	report := DetailedReport{}
-	xml.Unmarshal(response, &report)
+
+	if err := xml.Unmarshal(response, &report); err != nil {
+		return DetailedReport{}, fmt.Errorf("Could not parse the detailed report for build id %d", buildId)
+	}
+
+	// Flatten the flaws, which are nested under severity and category
+	for _, severity := range report.Severities {
+		for _, category := range severity.Categories {
+			for _, flaw := range category.Flaws {
+				flaw.Severity = severity.Level
+				flaw.CategoryName = category.Name
+				report.Flaws = append(report.Flaws, flaw)
+			}
+
+			for _, flaw := range category.DynamicFlaws {
+				flaw.Severity = severity.Level
+				flaw.CategoryName = category.Name
+				report.DynamicFlaws = append(report.DynamicFlaws, flaw)
+			}
+
+			for _, flaw := range category.ManualFlaws {
+				flaw.Severity = severity.Level
+				flaw.CategoryName = category.Name
+				report.ManualFlaws = append(report.ManualFlaws, flaw)
+			}
+		}
+	}
 
 	// Dedupe the module list which can contain duplicate entries
 	report.StaticAnalysis.Modules = dedupeArray(report.StaticAnalysis.Modules)
@@ -95,16 +185,63 @@ func (api API) getDetailedReport(buildId int) DetailedReport {
 		return report.Flaws[i].ID < report.Flaws[j].ID
 	})
 
-	report.SubmittedDate = parseVeracodeDate(report.StaticAnalysis.SubmittedDate).Local()
-	report.PublishedDate = parseVeracodeDate(report.StaticAnalysis.PublishedDate).Local()
+	sort.Slice(report.DynamicFlaws, func(i, j int) bool {
+		return report.DynamicFlaws[i].ID < report.DynamicFlaws[j].ID
+	})
+
+	sort.Slice(report.ManualFlaws, func(i, j int) bool {
+		return report.ManualFlaws[i].ID < report.ManualFlaws[j].ID
+	})
+
+	for index := range report.Flaws {
+		report.Flaws[index].MatchId = report.Flaws[index].ID
+		report.Flaws[index].FirstFoundDate = tryParseVeracodeDate(report.Flaws[index].DateFirstOccurrence)
+		report.Flaws[index].GracePeriodExpiryDate = tryParseVeracodeDate(report.Flaws[index].GracePeriodExpires)
+	}
+
+	for index := range report.DynamicFlaws {
+		report.DynamicFlaws[index].MatchId = report.DynamicFlaws[index].ID
+	}
+
+	for index := range report.ManualFlaws {
+		report.ManualFlaws[index].MatchId = report.ManualFlaws[index].ID
+	}
+
+	// Keep the first date which could not be parsed
+	var dateErr error
+
+	parseDate := func(date string) time.Time {
+		parsed, err := parseVeracodeDate(date)
+
+		if err != nil && dateErr == nil {
+			dateErr = err
+		}
+
+		return parsed.Local()
+	}
+
+	report.SubmittedDate = parseDate(report.StaticAnalysis.SubmittedDate)
+	report.PublishedDate = parseDate(report.StaticAnalysis.PublishedDate)
 	report.Duration = report.PublishedDate.Sub(report.SubmittedDate)
 
-	return report
+	// Only present when a dynamic scan is linked to this build
+	if len(report.DynamicAnalysis.SubmittedDateString) > 0 && len(report.DynamicAnalysis.PublishedDateString) > 0 {
+		report.DynamicAnalysis.SubmittedDate = parseDate(report.DynamicAnalysis.SubmittedDateString)
+		report.DynamicAnalysis.PublishedDate = parseDate(report.DynamicAnalysis.PublishedDateString)
+		report.DynamicAnalysis.Duration = report.DynamicAnalysis.PublishedDate.Sub(report.DynamicAnalysis.SubmittedDate)
+	}
+
+	if dateErr != nil {
+		return DetailedReport{}, dateErr
+	}
+
+	return report, nil
 }
 
-func (report DetailedReport) getReviewModulesUrl(region string) string {
-	return fmt.Sprintf("%s/auth/index.jsp#AnalyzeAppModuleList:%d:%d:%d:%d:%d::::%d",
+func (report DetailedReport) getPlatformUrl(region, page string) string {
+	return fmt.Sprintf("%s/auth/index.jsp#%s:%d:%d:%d:%d:%d::::%d",
 		parseBaseUrlFromRegion(region),
+		page,
 		report.AccountId,
 		report.AppId,
 		report.BuildId,
@@ -113,15 +250,12 @@ func (report DetailedReport) getReviewModulesUrl(region string) string {
 		report.SandboxId)
 }
 
+func (report DetailedReport) getReviewModulesUrl(region string) string {
+	return report.getPlatformUrl(region, "AnalyzeAppModuleList")
+}
+
 func (report DetailedReport) getTriageFlawsUrl(region string) string {
-	return fmt.Sprintf("%s/auth/index.jsp#ReviewResultsStaticFlaws:%d:%d:%d:%d:%d::::%d",
-		parseBaseUrlFromRegion(region),
-		report.AccountId,
-		report.AppId,
-		report.BuildId,
-		report.AnalysisId,
-		report.StaticAnalysisUnitId,
-		report.SandboxId)
+	return report.getPlatformUrl(region, "ReviewResultsStaticFlaws")
 }
 
 func (report DetailedReport) getPolicyAffectingFlawCount() int {
@@ -173,9 +307,9 @@ func (report DetailedReport) getOpenNonPolicyAffectingFlawCount() int {
 	return count
 }
 
-func (report DetailedReport) isFlawInReport(flawId int) bool {
+func (report DetailedReport) isFlawInReport(matchId int) bool {
 	for _, flaw := range report.Flaws {
-		if flaw.ID == flawId {
+		if flaw.MatchId == matchId {
 			return true
 		}
 	}
@@ -192,3 +326,28 @@ func (module DetailedReportModule) isModuleNameInDetailedReportModuleArray(modul
 
 	return false
 }
+
+// Returns the flaw in this report paired with a flaw from the other scan
+func (report DetailedReport) getMatchingFlaw(otherSideFlaw DetailedReportFlaw) DetailedReportFlaw {
+	for _, flaw := range report.Flaws {
+		if flaw.MatchId == otherSideFlaw.MatchId {
+			return flaw
+		}
+	}
+
+	return DetailedReportFlaw{}
+}
+
+func (report DetailedReport) getFlaw(flawId int) DetailedReportFlaw {
+	for _, flaw := range report.Flaws {
+		if flaw.ID == flawId {
+			return flaw
+		}
+	}
+
+	return DetailedReportFlaw{}
+}
+
+func (report DetailedReport) isModuleSelected(moduleName string) bool {
+	return DetailedReportModule{Name: moduleName}.isModuleNameInDetailedReportModuleArray(report.StaticAnalysis.Modules)
+}
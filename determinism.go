@@ -0,0 +1,327 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+type DeterminismRun struct {
+	BuildId           int
+	Report            DetailedReport
+	PrescanFileList   PrescanFileList
+	PrescanModuleList PrescanModuleList
+}
+
+// The build IDs each item (a flaw, selected module or prescan issue) was reported in
+type determinismOccurrence struct {
+	description string
+	severity    int
+	buildIds    []int
+}
+
+func runDeterminism(vid, vkey, profile, region, builds, matchMode string, pathRules PathRules) {
+	var references []string
+
+	for _, reference := range strings.Split(builds, ",") {
+		if reference = strings.TrimSpace(reference); len(reference) > 0 {
+			references = append(references, reference)
+		}
+	}
+
+	if len(references) < 2 {
+		color.HiRed("Error: At least two Veracode Platform URLs or build IDs must be specified. Expected: \"scan_compare -action determinism -builds https://analysiscenter.veracode.com/auth/index.jsp...,https://analysiscenter.veracode.com/auth/index.jsp...\"")
+		print("\nUsage:\n")
+		flag.PrintDefaults()
+		return
+	}
+
+	var buildIds []int
+
+	for _, reference := range references {
+		buildId := parseScanBuildId(reference)
+
+		if isInIntArray(buildId, buildIds) {
+			color.HiRed(fmt.Sprintf("Error: Build id %d was specified more than once", buildId))
+			os.Exit(1)
+		}
+
+		buildIds = append(buildIds, buildId)
+	}
+
+	// Build IDs carry no region, so only Platform URLs are compared
+	var urls []string
+
+	for _, reference := range references {
+		if isPlatformURL(reference) {
+			urls = append(urls, reference)
+		}
+	}
+
+	for _, url := range urls {
+		if parseRegionFromUrl(url) != parseRegionFromUrl(urls[0]) {
+			color.HiRed("Error: Cannot analyse scans from different Veracode regions")
+			os.Exit(1)
+		}
+	}
+
+	regionToUse := region
+
+	if len(urls) > 0 {
+		regionToUse = getRegionToUse(region, urls...)
+	} else if region == "" {
+		regionToUse = "commercial"
+	}
+
+	api := getApiForSide(vid, vkey, profile, "", regionToUse, parseAccountIdFromScanUrl(references[0]))
+
+	colorPrintf(fmt.Sprintf("Analysing the determinism of %d scans in the %s region\n", len(buildIds), api.region))
+
+	var runs []DeterminismRun
+
+	for _, buildId := range buildIds {
+		run := api.getDeterminismRun(buildId)
+		pathRules.normaliseFlawPaths(&run.Report)
+
+		if collisions := pathRules.normaliseFileNames(&run.PrescanFileList); len(collisions) > 0 {
+			color.HiYellow(fmt.Sprintf("Warning: %s", getFormattedPathRuleCollisions(fmt.Sprintf("%d", buildId), collisions)))
+		}
+
+		runs = append(runs, run)
+	}
+
+	reportDeterminism(api.region, runs, matchMode)
+}
+
+func (api API) getDeterminismRun(buildId int) DeterminismRun {
+	run := DeterminismRun{BuildId: buildId, Report: api.getDetailedReport(buildId)}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		run.PrescanFileList = api.getPrescanFileList(run.Report.AppId, buildId)
+	}()
+
+	go func() {
+		defer wg.Done()
+		run.PrescanModuleList = api.getPrescanModuleList(run.Report.AppId, buildId)
+	}()
+
+	wg.Wait()
+
+	return run
+}
+
+// Every run is paired against the first run, so flaws share a match ID with the equivalent flaw in the first run
+func getDeterminismData(region string, firstRun, run DeterminismRun, matchMode string) Data {
+	// Copy the first run's flaws as matching sets fields on both sides
+	firstRunReport := firstRun.Report
+	firstRunReport.Flaws = append([]DetailedReportFlaw{}, firstRun.Report.Flaws...)
+
+	data := Data{
+		ScanARegion:            region,
+		ScanBRegion:            region,
+		ScanAReport:            firstRunReport,
+		ScanBReport:            run.Report,
+		ScanAPrescanFileList:   firstRun.PrescanFileList,
+		ScanBPrescanFileList:   run.PrescanFileList,
+		ScanAPrescanModuleList: firstRun.PrescanModuleList,
+		ScanBPrescanModuleList: run.PrescanModuleList,
+	}
+
+	data.matchFlaws(matchMode)
+	data.matchRelocatedFlaws()
+
+	return data
+}
+
+func addDeterminismOccurrence(occurrences map[string]*determinismOccurrence, key, description string, severity, buildId int) {
+	occurrence, found := occurrences[key]
+
+	if !found {
+		occurrence = &determinismOccurrence{description: description, severity: severity}
+		occurrences[key] = occurrence
+	}
+
+	if !isInIntArray(buildId, occurrence.buildIds) {
+		occurrence.buildIds = append(occurrence.buildIds, buildId)
+	}
+}
+
+// Flaws paired with the first run share its issue ID. When matching by fingerprint, flaws not in the first run
+// are given a negative match ID from their own run, so they are keyed by their strongest fingerprint instead
+func getDeterminismFlawKey(flaw DetailedReportFlaw) string {
+	if flaw.MatchId > 0 {
+		return fmt.Sprintf("%d", flaw.MatchId)
+	}
+
+	for _, tier := range flawMatchTiers {
+		if fingerprint := tier.getFingerprint(flaw); len(fingerprint) > 0 {
+			return fingerprint
+		}
+	}
+
+	return fmt.Sprintf("%d", flaw.MatchId)
+}
+
+func addPrescanIssueOccurrences(occurrences map[string]*determinismOccurrence, run DeterminismRun) {
+	for _, module := range run.PrescanModuleList.Modules {
+		for _, status := range strings.Split(module.Status, ",") {
+			if status = strings.TrimSpace(status); len(status) == 0 || status == "OK" {
+				continue
+			}
+
+			description := fmt.Sprintf("\"%s\": %s", module.Name, status)
+			addDeterminismOccurrence(occurrences, description, description, 0, run.BuildId)
+		}
+
+		for _, issue := range module.Issues {
+			description := fmt.Sprintf("\"%s\": %s", module.Name, issue.Details)
+			addDeterminismOccurrence(occurrences, description, description, 0, run.BuildId)
+		}
+	}
+}
+
+// Returns the items not reported in every run, highest severity and least frequent first
+func getUnstableOccurrences(occurrences map[string]*determinismOccurrence, runCount int) []*determinismOccurrence {
+	var unstable []*determinismOccurrence
+
+	for _, occurrence := range occurrences {
+		if len(occurrence.buildIds) < runCount {
+			unstable = append(unstable, occurrence)
+		}
+	}
+
+	sort.Slice(unstable, func(i, j int) bool {
+		if unstable[i].severity != unstable[j].severity {
+			return unstable[i].severity > unstable[j].severity
+		}
+
+		if len(unstable[i].buildIds) != len(unstable[j].buildIds) {
+			return len(unstable[i].buildIds) < len(unstable[j].buildIds)
+		}
+
+		return unstable[i].description < unstable[j].description
+	})
+
+	return unstable
+}
+
+func getFormattedUnstableOccurrences(unstable []*determinismOccurrence, runs []DeterminismRun) string {
+	var report strings.Builder
+
+	for _, occurrence := range unstable {
+		var missingFromBuildIds []int
+
+		for _, run := range runs {
+			if !isInIntArray(run.BuildId, occurrence.buildIds) {
+				missingFromBuildIds = append(missingFromBuildIds, run.BuildId)
+			}
+		}
+
+		report.WriteString(fmt.Sprintf("%s: %s (not reported in build %s)\n",
+			occurrence.description,
+			color.HiYellowString("%d of %d scans", len(occurrence.buildIds), len(runs)),
+			getSortedIntArrayAsFormattedString(missingFromBuildIds)))
+	}
+
+	return report.String()
+}
+
+func reportDeterminism(region string, runs []DeterminismRun, matchMode string) {
+	firstRun := runs[0]
+	flawOccurrences := make(map[string]*determinismOccurrence)
+	moduleOccurrences := make(map[string]*determinismOccurrence)
+	prescanIssueOccurrences := make(map[string]*determinismOccurrence)
+
+	var uploads strings.Builder
+	var allUploadsIdentical = true
+	var engineVersions []string
+
+	for _, run := range runs {
+		data := getDeterminismData(region, firstRun, run, matchMode)
+
+		if run.BuildId != firstRun.BuildId {
+			similarity := data.getUploadSimilarity()
+
+			if similarity.Classification != UploadsIdentical {
+				allUploadsIdentical = false
+			}
+
+			uploads.WriteString(fmt.Sprintf("Build %d: %s (%d of %d files have the same name and MD5)\n",
+				run.BuildId,
+				similarity.Classification,
+				similarity.MatchingFiles,
+				similarity.ScanBFiles))
+		}
+
+		if !isStringInStringArray(run.Report.StaticAnalysis.EngineVersion, engineVersions) {
+			engineVersions = append(engineVersions, run.Report.StaticAnalysis.EngineVersion)
+		}
+
+		for _, flaw := range data.ScanBReport.Flaws {
+			addDeterminismOccurrence(flawOccurrences,
+				getDeterminismFlawKey(flaw),
+				fmt.Sprintf("%s %d (%s) %s", getFormattedSeverity(flaw.Severity), flaw.ID, getFormattedCwe(flaw.CWE), flaw.getFormattedLocation()),
+				flaw.Severity,
+				run.BuildId)
+		}
+
+		for _, module := range run.Report.StaticAnalysis.Modules {
+			addDeterminismOccurrence(moduleOccurrences, module.Name, fmt.Sprintf("\"%s\"", module.Name), 0, run.BuildId)
+		}
+
+		addPrescanIssueOccurrences(prescanIssueOccurrences, run)
+	}
+
+	printTitle(fmt.Sprintf("Upload Consistency (Compared Against Build %d)", firstRun.BuildId))
+	colorPrintf(uploads.String())
+
+	unstableFlaws := getUnstableOccurrences(flawOccurrences, len(runs))
+	unstableModules := getUnstableOccurrences(moduleOccurrences, len(runs))
+	unstablePrescanIssues := getUnstableOccurrences(prescanIssueOccurrences, len(runs))
+
+	if len(unstableFlaws) > 0 {
+		printTitle("Unstable Flaws")
+		colorPrintf(getFormattedUnstableOccurrences(unstableFlaws, runs))
+	}
+
+	if len(unstableModules) > 0 {
+		printTitle("Unstable Modules Selected As An Entry Point For Scanning")
+		colorPrintf(getFormattedUnstableOccurrences(unstableModules, runs))
+	}
+
+	if len(unstablePrescanIssues) > 0 {
+		printTitle("Unstable Prescan Issues")
+		colorPrintf(getFormattedUnstableOccurrences(unstablePrescanIssues, runs))
+	}
+
+	var summary strings.Builder
+
+	summary.WriteString(fmt.Sprintf("Scans analysed: %d\n", len(runs)))
+	summary.WriteString(fmt.Sprintf("Engine versions: %s\n", strings.Join(engineVersions, ", ")))
+	summary.WriteString(fmt.Sprintf("Stable flaws: %d, unstable flaws: %d\n", len(flawOccurrences)-len(unstableFlaws), len(unstableFlaws)))
+	summary.WriteString(fmt.Sprintf("Unstable selected modules: %d, unstable prescan issues: %d\n", len(unstableModules), len(unstablePrescanIssues)))
+
+	hasUnstableResults := len(unstableFlaws) > 0 || len(unstableModules) > 0 || len(unstablePrescanIssues) > 0
+
+	if !allUploadsIdentical {
+		summary.WriteString(color.HiYellowString("The uploads are not identical, so differences between the scans may be caused by code changes\n"))
+	} else if hasUnstableResults && len(engineVersions) > 1 {
+		summary.WriteString(color.HiYellowString("The uploads are identical but the results differ. The engine version also changed between these scans, which may explain the differences\n"))
+	} else if hasUnstableResults {
+		summary.WriteString(color.HiRedString("The uploads are identical and were scanned with the same engine version but the results differ. This indicates the scan engine is not deterministic\n"))
+	} else {
+		summary.WriteString(color.HiGreenString("The uploads are identical and every scan reported the same results\n"))
+	}
+
+	printTitle("Determinism Summary")
+	colorPrintf(summary.String())
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type DetailedReportDAST struct {
+	XMLName             xml.Name `xml:"dynamic-analysis"`
+	SubmittedDateString string   `xml:"submitted_date,attr"`
+	PublishedDateString string   `xml:"published_date,attr"`
+	ScanExitStatus      string   `xml:"scan_exit_status_desc,attr"`
+	SubmittedDate       time.Time
+	PublishedDate       time.Time
+	Duration            time.Duration
+}
+
+// Wraps the dynamic flaws in a report so they can be compared in the same way as static flaws
+func (report DetailedReport) getDynamicFlawReport() DetailedReport {
+	return DetailedReport{Flaws: report.DynamicFlaws}
+}
+
+func getDynamicFlawFingerprint(flaw DetailedReportFlaw) string {
+	return fmt.Sprintf("%d|%s|%s", flaw.CWE, strings.ToLower(strings.TrimSpace(flaw.URL)), flaw.VulnerableParameter)
+}
+
+// Issue IDs only line up within an application profile, so when static flaws are matched by
+// fingerprint dynamic flaws are matched by CWE, URL and parameter instead
+func (data *Data) matchDynamicFlaws() {
+	if !data.MatchedByFingerprint {
+		return
+	}
+
+	scanAFlawIdsByFingerprint := make(map[string][]int)
+
+	for _, flaw := range data.ScanAReport.DynamicFlaws {
+		fingerprint := getDynamicFlawFingerprint(flaw)
+		scanAFlawIdsByFingerprint[fingerprint] = append(scanAFlawIdsByFingerprint[fingerprint], flaw.ID)
+	}
+
+	for index, flaw := range data.ScanBReport.DynamicFlaws {
+		fingerprint := getDynamicFlawFingerprint(flaw)
+		candidates := scanAFlawIdsByFingerprint[fingerprint]
+
+		if len(candidates) == 0 {
+			data.ScanBReport.DynamicFlaws[index].MatchId = -flaw.ID
+			continue
+		}
+
+		data.ScanBReport.DynamicFlaws[index].MatchId = candidates[0]
+		scanAFlawIdsByFingerprint[fingerprint] = candidates[1:]
+	}
+}
+
+func (data Data) reportDynamicFlawDifferences() {
+	if len(data.ScanAReport.DynamicFlaws) == 0 && len(data.ScanBReport.DynamicFlaws) == 0 {
+		return
+	}
+
+	scanAReport := data.ScanAReport.getDynamicFlawReport()
+	scanBReport := data.ScanBReport.getDynamicFlawReport()
+
+	var stateReport strings.Builder
+	compareFlawStates(&stateReport, scanAReport, scanBReport)
+
+	if stateReport.Len() > 0 {
+		data.printTitle("Dynamic Flaw State Differences")
+		data.colorPrintf(stateReport.String())
+	}
+
+	var mitigationReport strings.Builder
+	compareFlawMitigations(&mitigationReport, scanAReport, scanBReport)
+
+	if mitigationReport.Len() > 0 {
+		data.printTitle("Dynamic Flaw Mitigation Differences")
+		data.colorPrintf(mitigationReport.String())
+	}
+
+	for _, section := range []struct {
+		title           string
+		policyAffecting bool
+		onlyClosed      bool
+	}{
+		{"Policy Affecting Open Dynamic Flaw Differences", true, false},
+		{"Non Policy Affecting Open Dynamic Flaw Differences", false, false},
+		{"Closed Dynamic Flaw Differences", false, true},
+	} {
+		var report strings.Builder
+
+		compareFlaws(&report, "A", scanAReport, scanBReport, section.policyAffecting, section.onlyClosed)
+		compareFlaws(&report, "B", scanBReport, scanAReport, section.policyAffecting, section.onlyClosed)
+
+		if report.Len() > 0 {
+			data.printTitle(section.title)
+			data.colorPrintf(report.String())
+		}
+	}
+
+	data.reportDynamicFlawDifferencesByUrl(scanAReport, scanBReport)
+}
+
+func (data Data) reportDynamicFlawDifferencesByUrl(scanAReport, scanBReport DetailedReport) {
+	flawsByUrl := make(map[string][]string)
+
+	for _, side := range []string{"A", "B"} {
+		thisSideReport, otherSideReport := scanAReport, scanBReport
+
+		if side == "B" {
+			thisSideReport, otherSideReport = scanBReport, scanAReport
+		}
+
+		for _, flaw := range getFlawsSortedBySeverity(thisSideReport.Flaws) {
+			if otherSideReport.isFlawInReport(flaw.MatchId) {
+				continue
+			}
+
+			var formattedParameter = ""
+
+			if len(flaw.VulnerableParameter) > 0 {
+				formattedParameter = fmt.Sprintf(", parameter \"%s\"", flaw.VulnerableParameter)
+			}
+
+			flawsByUrl[flaw.URL] = append(flawsByUrl[flaw.URL], fmt.Sprintf("  %s: %s %d (%s%s)\n",
+				getFormattedOnlyInSideString(side),
+				getFormattedSeverity(flaw.Severity),
+				flaw.ID,
+				getFormattedCwe(flaw.CWE),
+				formattedParameter))
+		}
+	}
+
+	urls := make([]string, 0, len(flawsByUrl))
+
+	for url := range flawsByUrl {
+		urls = append(urls, url)
+	}
+
+	sort.Strings(urls)
+
+	var report strings.Builder
+
+	for _, url := range urls {
+		report.WriteString(fmt.Sprintf("%s\n%s", url, strings.Join(flawsByUrl[url], "")))
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Dynamic Flaw Differences By URL")
+		data.colorPrintf(report.String())
+	}
+}
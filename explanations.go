@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	FlawDifferenceDrivenByCode          = "code"
+	FlawDifferenceDrivenByConfiguration = "configuration"
+	FlawDifferenceDrivenByEngine        = "engine"
+	FlawDifferenceDrivenByUnknown       = "unknown"
+)
+
+type flawExplanation struct {
+	side   string
+	driver string
+	cause  string
+}
+
+// Returns the most likely reason a flaw is only reported in this side's scan, based on the evidence in Data,
+// along with whether the difference is driven by code, configuration (i.e. module selection) or the engine
+func (data Data) explainFlawDifference(side string, flaw DetailedReportFlaw) (string, string) {
+	otherSide, otherReport, otherPrescanFileList, otherPrescanModuleList := "B", data.ScanBReport, data.ScanBPrescanFileList, data.ScanBPrescanModuleList
+	thisPrescanFileList, thisPrescanModuleList := data.ScanAPrescanFileList, data.ScanAPrescanModuleList
+
+	if side == "B" {
+		otherSide, otherReport, otherPrescanFileList, otherPrescanModuleList = "A", data.ScanAReport, data.ScanAPrescanFileList, data.ScanAPrescanModuleList
+		thisPrescanFileList, thisPrescanModuleList = data.ScanBPrescanFileList, data.ScanBPrescanModuleList
+	}
+
+	otherPrescanModule := otherPrescanModuleList.getFromName(flaw.Module)
+
+	if len(otherPrescanModule.Name) == 0 && len(otherPrescanFileList.getFromName(flaw.Module).Name) == 0 {
+		return FlawDifferenceDrivenByCode, fmt.Sprintf("Module \"%s\" was not present in scan %s", flaw.Module, otherSide)
+	}
+
+	if otherPrescanModule.HasFatalErrors {
+		return FlawDifferenceDrivenByConfiguration, fmt.Sprintf("Module \"%s\" was unscannable in scan %s%s", flaw.Module, otherSide, otherPrescanModule.getFatalReason())
+	}
+
+	if !otherReport.isModuleSelected(flaw.Module) {
+		return FlawDifferenceDrivenByConfiguration, fmt.Sprintf("Module \"%s\" was not selected as an entry point in scan %s", flaw.Module, otherSide)
+	}
+
+	if missingSupportingFileCount := getMissingSupportedFileCountFromPreScanModuleStatus(otherPrescanModule); missingSupportingFileCount > 0 {
+		return FlawDifferenceDrivenByConfiguration, fmt.Sprintf("Module \"%s\" was missing %d supporting files in scan %s", flaw.Module, missingSupportingFileCount, otherSide)
+	}
+
+	thisModuleMD5 := getModuleMD5(flaw.Module, thisPrescanFileList, thisPrescanModuleList)
+	otherModuleMD5 := getModuleMD5(flaw.Module, otherPrescanFileList, otherPrescanModuleList)
+
+	if len(thisModuleMD5) > 0 && len(otherModuleMD5) > 0 && thisModuleMD5 != otherModuleMD5 {
+		return FlawDifferenceDrivenByCode, fmt.Sprintf("Module \"%s\" changed (different MD5)", flaw.Module)
+	}
+
+	if data.ScanAReport.StaticAnalysis.EngineVersion != data.ScanBReport.StaticAnalysis.EngineVersion {
+		return FlawDifferenceDrivenByEngine, "The scan engine version changed and the module is otherwise identical"
+	}
+
+	return FlawDifferenceDrivenByUnknown, "Unknown - the module is identical and was scanned with the same engine version"
+}
+
+func (data Data) reportExplanations() {
+	explainedFlawIds := make(map[flawExplanation][]int)
+	similarity := data.getUploadSimilarity()
+
+	for _, side := range []string{"A", "B"} {
+		thisReport, otherReport := data.ScanAReport, data.ScanBReport
+
+		if side == "B" {
+			thisReport, otherReport = data.ScanBReport, data.ScanAReport
+		}
+
+		for _, flaw := range thisReport.Flaws {
+			if otherReport.isFlawInReport(flaw.MatchId) {
+				continue
+			}
+
+			_, cause := data.explainFlawDifference(side, flaw)
+			key := flawExplanation{side, data.getFlawDifferenceDriver(side, flaw, similarity), cause}
+			explainedFlawIds[key] = append(explainedFlawIds[key], flaw.ID)
+		}
+	}
+
+	sortedKeys := make([]flawExplanation, 0, len(explainedFlawIds))
+	for key := range explainedFlawIds {
+		sortedKeys = append(sortedKeys, key)
+	}
+
+	// Scan A first, then the largest groups first
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		if sortedKeys[i].side != sortedKeys[j].side {
+			return sortedKeys[i].side < sortedKeys[j].side
+		}
+
+		if len(explainedFlawIds[sortedKeys[i]]) != len(explainedFlawIds[sortedKeys[j]]) {
+			return len(explainedFlawIds[sortedKeys[i]]) > len(explainedFlawIds[sortedKeys[j]])
+		}
+
+		return sortedKeys[i].cause+sortedKeys[i].driver < sortedKeys[j].cause+sortedKeys[j].driver
+	})
+
+	var report strings.Builder
+
+	for _, key := range sortedKeys {
+		flawIds := explainedFlawIds[key]
+		var noun = "flaws"
+
+		if len(flawIds) == 1 {
+			noun = "flaw"
+		}
+
+		var driver = "code-driven"
+
+		if key.driver == FlawDifferenceDrivenByEngine || key.driver == FlawDifferenceDrivenByConfiguration {
+			driver = "engine/config-driven"
+		} else if key.driver == FlawDifferenceDrivenByUnknown {
+			driver = "unknown driver"
+		}
+
+		report.WriteString(fmt.Sprintf("%s: %d %s = %s\n  Likely cause: %s (%s)\n",
+			getFormattedOnlyInSideString(key.side),
+			len(flawIds),
+			noun,
+			getSortedIntArrayAsFormattedString(flawIds),
+			key.cause,
+			driver))
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Likely Causes Of Flaw Differences")
+		data.colorPrintf(report.String())
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"os"
 	"sort"
+
+	"github.com/fatih/color"
 )
 
 type PrescanFileList struct {
@@ -21,8 +24,23 @@ type PrescanFile struct {
 }
 
 func (api API) getPrescanFileList(appId, buildId int) PrescanFileList {
+	fileList, err := api.tryGetPrescanFileList(appId, buildId)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
+		os.Exit(1)
+	}
+
+	return fileList
+}
+
+func (api API) tryGetPrescanFileList(appId, buildId int) (PrescanFileList, error) {
 	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/getfilelist.do?app_id=%d&build_id=%d", appId, buildId)
-	response := api.makeApiRequest(url, http.MethodGet)
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return PrescanFileList{}, err
+	}
 
 	fileList := PrescanFileList{}
 	xml.Unmarshal(response, &fileList)
@@ -32,7 +50,7 @@ func (api API) getPrescanFileList(appId, buildId int) PrescanFileList {
 		return fileList.Files[i].Name < fileList.Files[j].Name
 	})
 
-	return fileList
+	return fileList, nil
 }
 
 func (fileList PrescanFileList) getFromName(moduleName string) PrescanFile {
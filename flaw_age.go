@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+var flawAgeBuckets = []struct {
+	name    string
+	maxDays int
+}{
+	{"Under 30 days", 30},
+	{"30 to 90 days", 90},
+	{"90 to 180 days", 180},
+	{"180 to 365 days", 365},
+	{"Over 1 year", -1},
+}
+
+// Flaw age and grace periods are measured at the time each scan was published, not now
+func (flaw DetailedReportFlaw) getAge(report DetailedReport) time.Duration {
+	if flaw.FirstFoundDate.IsZero() {
+		return 0
+	}
+
+	return report.PublishedDate.Sub(flaw.FirstFoundDate)
+}
+
+func (flaw DetailedReportFlaw) isGracePeriodBreached(report DetailedReport) bool {
+	return flaw.isFlawOpen() && !flaw.GracePeriodExpiryDate.IsZero() && flaw.GracePeriodExpiryDate.Before(report.PublishedDate)
+}
+
+func getFlawAgeBucket(age time.Duration) int {
+	days := int(age.Hours() / 24)
+
+	for index, bucket := range flawAgeBuckets {
+		if bucket.maxDays < 0 || days < bucket.maxDays {
+			return index
+		}
+	}
+
+	return len(flawAgeBuckets) - 1
+}
+
+func getOpenFlawAgeDistribution(report DetailedReport) []int {
+	distribution := make([]int, len(flawAgeBuckets))
+
+	for _, flaw := range report.Flaws {
+		if flaw.isFlawOpen() && !flaw.FirstFoundDate.IsZero() {
+			distribution[getFlawAgeBucket(flaw.getAge(report))]++
+		}
+	}
+
+	return distribution
+}
+
+func (data Data) reportFlawAgeDifferences() {
+	data.reportGracePeriodDifferences()
+	data.reportOpenFlawAgeDistribution()
+}
+
+func getFormattedGracePeriod(flaw DetailedReportFlaw, report DetailedReport) string {
+	if flaw.ID == 0 {
+		return "not reported"
+	}
+
+	if !flaw.isFlawOpen() {
+		return "closed"
+	}
+
+	if flaw.GracePeriodExpiryDate.IsZero() {
+		return "no grace period"
+	}
+
+	if flaw.isGracePeriodBreached(report) {
+		return color.HiRedString("breached %s ago", formatDuration(report.PublishedDate.Sub(flaw.GracePeriodExpiryDate)))
+	}
+
+	return fmt.Sprintf("expires in %s", formatDuration(flaw.GracePeriodExpiryDate.Sub(report.PublishedDate)))
+}
+
+func (data Data) reportGracePeriodDifferences() {
+	var report strings.Builder
+
+	writeGracePeriodChange := func(scanAFlaw, scanBFlaw DetailedReportFlaw) {
+		flaw := scanAFlaw
+
+		if flaw.ID == 0 {
+			flaw = scanBFlaw
+		}
+
+		var change = color.HiRedString("Now in breach")
+
+		if scanAFlaw.isGracePeriodBreached(data.ScanAReport) {
+			change = color.HiGreenString("No longer in breach")
+		}
+
+		var firstFound = "unknown"
+
+		if !flaw.FirstFoundDate.IsZero() {
+			firstFound = flaw.FirstFoundDate.Format("2006-01-02")
+		}
+
+		report.WriteString(fmt.Sprintf("%s %d (%s): %s, first found %s, %s: %s, %s: %s\n",
+			getFormattedSeverity(flaw.Severity),
+			flaw.ID,
+			getFormattedCwe(flaw.CWE),
+			change,
+			firstFound,
+			getFormattedSideString("A"),
+			getFormattedGracePeriod(scanAFlaw, data.ScanAReport),
+			getFormattedSideString("B"),
+			getFormattedGracePeriod(scanBFlaw, data.ScanBReport)))
+	}
+
+	for _, scanAFlaw := range getFlawsSortedBySeverity(data.ScanAReport.Flaws) {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+
+		if scanAFlaw.isGracePeriodBreached(data.ScanAReport) != scanBFlaw.isGracePeriodBreached(data.ScanBReport) {
+			writeGracePeriodChange(scanAFlaw, scanBFlaw)
+		}
+	}
+
+	for _, scanBFlaw := range getFlawsSortedBySeverity(data.ScanBReport.Flaws) {
+		if !data.ScanAReport.isFlawInReport(scanBFlaw.MatchId) && scanBFlaw.isGracePeriodBreached(data.ScanBReport) {
+			writeGracePeriodChange(DetailedReportFlaw{}, scanBFlaw)
+		}
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Grace Period Breach Differences")
+		data.colorPrintf(report.String())
+	}
+}
+
+func (data Data) reportOpenFlawAgeDistribution() {
+	scanADistribution := getOpenFlawAgeDistribution(data.ScanAReport)
+	scanBDistribution := getOpenFlawAgeDistribution(data.ScanBReport)
+
+	var total = 0
+
+	for index := range flawAgeBuckets {
+		total += scanADistribution[index] + scanBDistribution[index]
+	}
+
+	// The first found date is not always present
+	if total == 0 {
+		return
+	}
+
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("%-16s %s   %s\n", "Age", getFormattedSideStringWithMessage("A", fmt.Sprintf("%5s", "A")), getFormattedSideStringWithMessage("B", fmt.Sprintf("%5s", "B"))))
+
+	for index, bucket := range flawAgeBuckets {
+		var delta = ""
+
+		if scanBDistribution[index] != scanADistribution[index] {
+			delta = fmt.Sprintf(" (%+d)", scanBDistribution[index]-scanADistribution[index])
+		}
+
+		report.WriteString(fmt.Sprintf("%-16s %5d   %5d%s\n", bucket.name, scanADistribution[index], scanBDistribution[index], delta))
+	}
+
+	data.printTitle("Open Flaw Age")
+	data.colorPrintf(report.String())
+}
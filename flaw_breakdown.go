@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type flawBreakdownRow struct {
+	key         string
+	scanAOpen   int
+	scanBOpen   int
+	newFlaws    int
+	closedFlaws int
+}
+
+func (row flawBreakdownRow) getDelta() int {
+	return row.scanBOpen - row.scanAOpen
+}
+
+func (row flawBreakdownRow) hasChanged() bool {
+	return row.newFlaws > 0 || row.closedFlaws > 0 || row.getDelta() != 0
+}
+
+// New flaws are open in B but not open in A, closed flaws are open in A but not open in B
+func (data Data) getFlawBreakdown(getKey func(flaw DetailedReportFlaw) string) []flawBreakdownRow {
+	rows := make(map[string]*flawBreakdownRow)
+
+	getRow := func(flaw DetailedReportFlaw) *flawBreakdownRow {
+		key := getKey(flaw)
+
+		if _, found := rows[key]; !found {
+			rows[key] = &flawBreakdownRow{key: key}
+		}
+
+		return rows[key]
+	}
+
+	for _, flaw := range data.ScanAReport.Flaws {
+		if flaw.isFlawOpen() {
+			getRow(flaw).scanAOpen++
+		}
+	}
+
+	for _, flaw := range data.ScanBReport.Flaws {
+		if flaw.isFlawOpen() {
+			getRow(flaw).scanBOpen++
+		}
+	}
+
+	for _, flaw := range getFlawsClosedInOtherScan(data.ScanBReport, data.ScanAReport) {
+		getRow(flaw).newFlaws++
+	}
+
+	for _, flaw := range getFlawsClosedInOtherScan(data.ScanAReport, data.ScanBReport) {
+		getRow(flaw).closedFlaws++
+	}
+
+	var changedRows []flawBreakdownRow
+
+	for _, row := range rows {
+		if row.hasChanged() {
+			changedRows = append(changedRows, *row)
+		}
+	}
+
+	// Largest change first
+	sort.Slice(changedRows, func(i, j int) bool {
+		if abs(changedRows[i].getDelta()) != abs(changedRows[j].getDelta()) {
+			return abs(changedRows[i].getDelta()) > abs(changedRows[j].getDelta())
+		}
+
+		return changedRows[i].key < changedRows[j].key
+	})
+
+	return changedRows
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+func (data Data) reportFlawBreakdown(title, keyName string, rows []flawBreakdownRow) {
+	if len(rows) == 0 {
+		return
+	}
+
+	var keyWidth = len(keyName)
+
+	for _, row := range rows {
+		if len(row.key) > keyWidth {
+			keyWidth = len(row.key)
+		}
+	}
+
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("%-*s  %s  %s  %5s  %6s  %5s\n",
+		keyWidth,
+		keyName,
+		getFormattedSideStringWithMessage("A", fmt.Sprintf("%6s", "Open A")),
+		getFormattedSideStringWithMessage("B", fmt.Sprintf("%6s", "Open B")),
+		"New",
+		"Closed",
+		"Delta"))
+
+	for _, row := range rows {
+		var delta = fmt.Sprintf("%+5d", row.getDelta())
+
+		if row.getDelta() > 0 {
+			delta = color.HiRedString(delta)
+		} else if row.getDelta() < 0 {
+			delta = color.HiGreenString(delta)
+		}
+
+		report.WriteString(fmt.Sprintf("%-*s  %6d  %6d  %5d  %6d  %s\n", keyWidth, row.key, row.scanAOpen, row.scanBOpen, row.newFlaws, row.closedFlaws, delta))
+	}
+
+	data.printTitle(title)
+	data.colorPrintf(report.String())
+}
+
+func (data Data) reportFlawBreakdowns() {
+	data.reportFlawBreakdown("Open Flaw Differences By Module", "Module", data.getFlawBreakdown(func(flaw DetailedReportFlaw) string {
+		return flaw.Module
+	}))
+
+	data.reportFlawBreakdown("Open Flaw Differences By Source File", "Source file", data.getFlawBreakdown(func(flaw DetailedReportFlaw) string {
+		return flaw.getSourceFilePath()
+	}))
+}
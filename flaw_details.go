@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+func getModuleMD5(moduleName string, prescanFileList PrescanFileList, prescanModuleList PrescanModuleList) string {
+	if md5 := prescanModuleList.getFromName(moduleName).MD5; len(md5) > 0 {
+		return md5
+	}
+
+	return prescanFileList.getFromName(moduleName).MD5
+}
+
+func formatYesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+// Looks up the flaw by issue ID in scan A first, then scan B, and returns the pair
+func (data Data) getFlawPair(flawId int) (DetailedReportFlaw, DetailedReportFlaw) {
+	scanAFlaw := data.ScanAReport.getFlaw(flawId)
+
+	if scanAFlaw.ID != 0 {
+		return scanAFlaw, data.ScanBReport.getMatchingFlaw(scanAFlaw)
+	}
+
+	scanBFlaw := data.ScanBReport.getFlaw(flawId)
+
+	if scanBFlaw.ID != 0 {
+		return data.ScanAReport.getMatchingFlaw(scanBFlaw), scanBFlaw
+	}
+
+	return DetailedReportFlaw{}, DetailedReportFlaw{}
+}
+
+// When this scan did not report the flaw the module details are still shown, using the other scan's module
+func (data Data) getFlawDetails(side string, flaw, otherFlaw DetailedReportFlaw) []string {
+	report, region, prescanFileList, prescanModuleList, otherPrescanFileList, otherPrescanModuleList := data.ScanAReport, data.ScanARegion, data.ScanAPrescanFileList, data.ScanAPrescanModuleList, data.ScanBPrescanFileList, data.ScanBPrescanModuleList
+
+	if side == "B" {
+		report, region, prescanFileList, prescanModuleList, otherPrescanFileList, otherPrescanModuleList = data.ScanBReport, data.ScanBRegion, data.ScanBPrescanFileList, data.ScanBPrescanModuleList, data.ScanAPrescanFileList, data.ScanAPrescanModuleList
+	}
+
+	var module = flaw.Module
+
+	if flaw.ID == 0 {
+		module = otherFlaw.Module
+	}
+
+	moduleMD5 := getModuleMD5(module, prescanFileList, prescanModuleList)
+	otherModuleMD5 := getModuleMD5(module, otherPrescanFileList, otherPrescanModuleList)
+	var moduleMD5Changed = "Unknown"
+
+	if len(moduleMD5) > 0 && len(otherModuleMD5) > 0 {
+		moduleMD5Changed = formatYesNo(moduleMD5 != otherModuleMD5)
+	}
+
+	moduleSelected := formatYesNo(report.isModuleSelected(module))
+	moduleMD5Details := fmt.Sprintf("%s (changed = %s)", moduleMD5, moduleMD5Changed)
+
+	if flaw.ID == 0 {
+		return []string{"Not reported", "", "", "", "", "", "", "", "", "", "", "", "", moduleSelected, moduleMD5Details, report.getTriageFlawsUrl(region)}
+	}
+
+	return []string{
+		fmt.Sprintf("%d", flaw.ID),
+		getFormattedCwe(flaw.CWE),
+		fmt.Sprintf("%s (%d)", getSeverityName(flaw.Severity), flaw.Severity),
+		flaw.CategoryName,
+		flaw.Module,
+		flaw.SourceFilePath + flaw.SourceFile,
+		fmt.Sprintf("%d", flaw.LineNumber),
+		flaw.FunctionPrototype,
+		flaw.RemediationStatus,
+		cases.Title(language.English).String(flaw.MitigationStatus),
+		fmt.Sprintf("%d", flaw.RemediationEffort),
+		formatYesNo(flaw.AffectsPolicyCompliance),
+		formatYesNo(flaw.isFlawOpen()),
+		moduleSelected,
+		moduleMD5Details,
+		report.getTriageFlawsUrl(region),
+	}
+}
+
+func (data Data) reportFlawDetails(flawId int) {
+	scanAFlaw, scanBFlaw := data.getFlawPair(flawId)
+
+	if scanAFlaw.ID == 0 && scanBFlaw.ID == 0 {
+		color.HiRed(fmt.Sprintf("Error: Flaw %d was not found in either scan", flawId))
+		os.Exit(1)
+	}
+
+	labels := []string{"Issue ID", "CWE", "Severity", "Category", "Module", "Source file", "Line", "Function", "Remediation status", "Mitigation status", "Remediation effort", "Affects policy", "Open", "Module selected", "Module MD5", "Triage flaws URL"}
+	scanADetails := data.getFlawDetails("A", scanAFlaw, scanBFlaw)
+	scanBDetails := data.getFlawDetails("B", scanBFlaw, scanAFlaw)
+
+	var columnWidth = 1
+
+	for _, detail := range scanADetails[:len(scanADetails)-1] {
+		if len(detail) > columnWidth {
+			columnWidth = len(detail)
+		}
+	}
+
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("%-20s%s  %s\n", "", getFormattedSideStringWithMessage("A", fmt.Sprintf("%-*s", columnWidth, "A")), getFormattedSideString("B")))
+
+	for index, label := range labels {
+		// The URLs are too long to show side by side
+		if label == "Triage flaws URL" {
+			report.WriteString(fmt.Sprintf("%-20s%s: %s\n%-20s%s: %s\n", label+":", getFormattedSideString("A"), scanADetails[index], "", getFormattedSideString("B"), scanBDetails[index]))
+			continue
+		}
+
+		line := fmt.Sprintf("%-20s%-*s  %s\n", label+":", columnWidth, scanADetails[index], scanBDetails[index])
+
+		if scanAFlaw.ID != 0 && scanBFlaw.ID != 0 && scanADetails[index] != scanBDetails[index] && label != "Module MD5" {
+			line = color.HiYellowString(line)
+		}
+
+		report.WriteString(line)
+	}
+
+	var cwe = scanAFlaw.CWE
+
+	if scanAFlaw.ID == 0 {
+		cwe = scanBFlaw.CWE
+	}
+
+	if cweDetails := getCweCatalogueDetails(cwe); len(cweDetails) > 0 {
+		report.WriteString(fmt.Sprintf("\n%s: %s\n", getFormattedCwe(cwe), cweDetails))
+	}
+
+	if scanAFlaw.ID == 0 {
+		report.WriteString(color.HiYellowString("\nThis flaw was only reported in scan B\n"))
+	} else if scanBFlaw.ID == 0 {
+		report.WriteString(color.HiYellowString("\nThis flaw was only reported in scan A\n"))
+	}
+
+	data.printTitle(fmt.Sprintf("Flaw %d", flawId))
+	data.colorPrintf(report.String())
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type FlawFilter struct {
+	CWEs          []int
+	MinSeverity   int
+	ModulePattern string
+	FilePattern   string
+	PolicyOnly    bool
+	moduleRegex   *regexp.Regexp
+	fileRegex     *regexp.Regexp
+}
+
+func parseFlawFilter(cwes string, minSeverity int, modulePattern, filePattern string, policyOnly bool) FlawFilter {
+	filter := FlawFilter{MinSeverity: minSeverity, ModulePattern: modulePattern, FilePattern: filePattern, PolicyOnly: policyOnly}
+
+	for _, cwe := range strings.Split(cwes, ",") {
+		cwe = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(cwe)), "CWE-")
+
+		if len(cwe) == 0 {
+			continue
+		}
+
+		cweId, err := strconv.Atoi(cwe)
+
+		if err != nil {
+			color.HiRed(fmt.Sprintf("Error: Invalid value for -cwe \"%s\". Expected a comma-separated list of CWE IDs, e.g. \"79,89\"", cwes))
+			os.Exit(1)
+		}
+
+		filter.CWEs = append(filter.CWEs, cweId)
+	}
+
+	if minSeverity < 0 || minSeverity >= len(severityNames) {
+		color.HiRed("Error: Invalid value for -min-severity. Must be between 0 and 5")
+		os.Exit(1)
+	}
+
+	if len(modulePattern) > 0 {
+		filter.moduleRegex = globToRegex(modulePattern, true)
+	}
+
+	if len(filePattern) > 0 {
+		filter.fileRegex = globToRegex(filePattern, true)
+	}
+
+	return filter
+}
+
+// Supports "*" within a path segment, "**" across path segments and "?" for a single character
+func globToRegex(pattern string, ignoreCase bool) *regexp.Regexp {
+	var expression strings.Builder
+
+	if ignoreCase {
+		expression.WriteString("(?i)")
+	}
+
+	expression.WriteString("^")
+
+	// Walk runes rather than bytes so non-ASCII characters are quoted whole
+	runes := []rune(pattern)
+
+	for index := 0; index < len(runes); index++ {
+		switch {
+		case strings.HasPrefix(string(runes[index:]), "**/"):
+			expression.WriteString("(.*/)?")
+			index += 2
+		case strings.HasPrefix(string(runes[index:]), "**"):
+			expression.WriteString(".*")
+			index++
+		case runes[index] == '*':
+			expression.WriteString("[^/]*")
+		case runes[index] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(runes[index])))
+		}
+	}
+
+	expression.WriteString("$")
+
+	return regexp.MustCompile(expression.String())
+}
+
+func (filter FlawFilter) isEmpty() bool {
+	return len(filter.CWEs) == 0 && filter.MinSeverity == 0 && filter.moduleRegex == nil && filter.fileRegex == nil && !filter.PolicyOnly
+}
+
+func (flaw DetailedReportFlaw) getSourceFilePath() string {
+	return strings.TrimPrefix(strings.ReplaceAll(flaw.SourceFilePath+flaw.SourceFile, "\\", "/"), "/")
+}
+
+func (filter FlawFilter) matches(flaw DetailedReportFlaw) bool {
+	if len(filter.CWEs) > 0 && !isInIntArray(flaw.CWE, filter.CWEs) {
+		return false
+	}
+
+	if flaw.Severity < filter.MinSeverity {
+		return false
+	}
+
+	if filter.moduleRegex != nil && !filter.moduleRegex.MatchString(flaw.Module) {
+		return false
+	}
+
+	if filter.fileRegex != nil && !filter.fileRegex.MatchString(flaw.getSourceFilePath()) {
+		return false
+	}
+
+	if filter.PolicyOnly && !flaw.AffectsPolicyCompliance {
+		return false
+	}
+
+	return true
+}
+
+func (filter FlawFilter) String() string {
+	var parts []string
+
+	if len(filter.CWEs) > 0 {
+		var cwes []string
+
+		for _, cwe := range filter.CWEs {
+			cwes = append(cwes, getFormattedCwe(cwe))
+		}
+
+		parts = append(parts, strings.Join(cwes, ", "))
+	}
+
+	if filter.MinSeverity > 0 {
+		parts = append(parts, fmt.Sprintf("severity %s or higher", getSeverityName(filter.MinSeverity)))
+	}
+
+	if len(filter.ModulePattern) > 0 {
+		parts = append(parts, fmt.Sprintf("module \"%s\"", filter.ModulePattern))
+	}
+
+	if len(filter.FilePattern) > 0 {
+		parts = append(parts, fmt.Sprintf("file \"%s\"", filter.FilePattern))
+	}
+
+	if filter.PolicyOnly {
+		parts = append(parts, "policy affecting only")
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// A flaw is kept if either it or the flaw it is paired with in the other scan matches the filter,
+// so a flaw which changed (e.g. severity) is not reported as only being in one scan
+func (data *Data) applyFlawFilter(filter FlawFilter) {
+	if filter.isEmpty() {
+		return
+	}
+
+	scanAFlaws := filterFlaws(filter, data.ScanAReport, data.ScanBReport)
+	scanBFlaws := filterFlaws(filter, data.ScanBReport, data.ScanAReport)
+
+	data.ScanAReport.setFilteredFlaws(scanAFlaws)
+	data.ScanBReport.setFilteredFlaws(scanBFlaws)
+	data.FlawFilter = filter
+}
+
+func filterFlaws(filter FlawFilter, thisSideReport, otherSideReport DetailedReport) []DetailedReportFlaw {
+	var flaws []DetailedReportFlaw
+
+	for _, flaw := range thisSideReport.Flaws {
+		otherSideFlaw := otherSideReport.getMatchingFlaw(flaw)
+
+		if filter.matches(flaw) || (otherSideFlaw.ID != 0 && filter.matches(otherSideFlaw)) {
+			flaws = append(flaws, flaw)
+		}
+	}
+
+	return flaws
+}
+
+// The flaw totals come from the report attributes, so recalculate them for the filtered set
+func (report *DetailedReport) setFilteredFlaws(flaws []DetailedReportFlaw) {
+	report.Flaws = flaws
+	report.TotalFlaws = len(flaws)
+	report.UnmitigatedFlaws = 0
+
+	for _, flaw := range flaws {
+		if flaw.MitigationStatus != "accepted" {
+			report.UnmitigatedFlaws++
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGlobToRegex(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		path       string
+		ignoreCase bool
+		expected   bool
+	}{
+		{"*.jar", "app.jar", true, true},
+		{"*.jar", "lib/app.jar", true, false},
+		{"src/*.java", "src/Login.java", true, true},
+		{"src/*.java", "src/com/Login.java", true, false},
+		{"src/**/*.java", "src/Login.java", true, true},
+		{"src/**/*.java", "src/com/example/Login.java", true, true},
+		{"src/**", "src/com/Login.java", true, true},
+		{"Login.?ava", "Login.java", true, true},
+		{"Login.?ava", "Login.jjava", true, false},
+		{"a+b(1).jar", "a+b(1).jar", true, true},
+		{"a+b(1).jar", "aab1.jar", true, false},
+		{"SRC/*.java", "src/Login.java", true, true},
+		{"SRC/*.java", "src/Login.java", false, false},
+		{"src/café/*", "src/café/x", true, true},
+		{"src/caf?/*", "src/café/x", true, true},
+		{"src/日本/**/*.go", "src/日本/a/b.go", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.path, func(t *testing.T) {
+			if result := globToRegex(test.pattern, test.ignoreCase).MatchString(test.path); result != test.expected {
+				t.Errorf("got %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestFlawFilterMatches(t *testing.T) {
+	flaw := DetailedReportFlaw{CWE: 89, Severity: 4, Module: "app.jar", SourceFilePath: "com/example/", SourceFile: "Login.java", AffectsPolicyCompliance: true}
+
+	tests := []struct {
+		name     string
+		filter   FlawFilter
+		expected bool
+	}{
+		{"empty filter", parseFlawFilter("", 0, "", "", false), true},
+		{"matching CWE", parseFlawFilter("79, CWE-89", 0, "", "", false), true},
+		{"other CWE", parseFlawFilter("79", 0, "", "", false), false},
+		{"severity at minimum", parseFlawFilter("", 4, "", "", false), true},
+		{"severity below minimum", parseFlawFilter("", 5, "", "", false), false},
+		{"matching module", parseFlawFilter("", 0, "*.jar", "", false), true},
+		{"other module", parseFlawFilter("", 0, "*.war", "", false), false},
+		{"matching file", parseFlawFilter("", 0, "", "com/**/*.java", false), true},
+		{"other file", parseFlawFilter("", 0, "", "org/**", false), false},
+		{"policy only", parseFlawFilter("", 0, "", "", true), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := test.filter.matches(flaw); result != test.expected {
+				t.Errorf("got %t, expected %t", result, test.expected)
+			}
+		})
+	}
+}
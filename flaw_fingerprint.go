@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+const (
+	MatchConfidenceHigh   = "High"
+	MatchConfidenceMedium = "Medium"
+	MatchConfidenceLow    = "Low"
+)
+
+// Each tier is tried in turn against the flaws not yet matched by a stronger tier
+var flawMatchTiers = []struct {
+	confidence     string
+	getFingerprint func(flaw DetailedReportFlaw) string
+}{
+	{MatchConfidenceHigh, func(flaw DetailedReportFlaw) string {
+		if !flaw.hasAllHashes() {
+			return ""
+		}
+
+		return fmt.Sprintf("%d|%s|%s|%s|%s|%s", flaw.CWE, normaliseModuleName(flaw.Module), normaliseSourceFile(flaw.SourceFile), flaw.ProcedureHash, flaw.PrototypeHash, flaw.StatementHash)
+	}},
+	// The module name can change between profiles, e.g. when it contains a version number
+	{MatchConfidenceMedium, func(flaw DetailedReportFlaw) string {
+		if !flaw.hasAllHashes() {
+			return ""
+		}
+
+		return fmt.Sprintf("%d|%s|%s|%s|%s", flaw.CWE, normaliseSourceFile(flaw.SourceFile), flaw.ProcedureHash, flaw.PrototypeHash, flaw.StatementHash)
+	}},
+	// Fall back to location when hashes are missing or have changed
+	{MatchConfidenceLow, func(flaw DetailedReportFlaw) string {
+		if len(flaw.SourceFile) == 0 || flaw.LineNumber == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf("%d|%s|%s|%d", flaw.CWE, normaliseModuleName(flaw.Module), normaliseSourceFile(flaw.SourceFile), flaw.LineNumber)
+	}},
+}
+
+func (flaw DetailedReportFlaw) hasAllHashes() bool {
+	return len(flaw.ProcedureHash) > 0 && len(flaw.PrototypeHash) > 0 && len(flaw.StatementHash) > 0
+}
+
+func normaliseModuleName(module string) string {
+	return strings.ToLower(strings.TrimSpace(module))
+}
+
+func normaliseSourceFile(sourceFile string) string {
+	return strings.ToLower(strings.Trim(strings.ReplaceAll(strings.TrimSpace(sourceFile), "\\", "/"), "/"))
+}
+
+func isFingerprintMatchingNeeded(data Data) bool {
+	return data.ScanARegion != data.ScanBRegion ||
+		data.ScanAReport.AccountId != data.ScanBReport.AccountId ||
+		data.ScanAReport.AppId != data.ScanBReport.AppId
+}
+
+// Issue IDs only line up within an application profile. In "auto" mode flaws are matched by
+// fingerprint when the scans are from different regions, accounts or application profiles
+func (data *Data) matchFlaws(matchMode string) {
+	if matchMode == "fingerprint" || (matchMode == "auto" && isFingerprintMatchingNeeded(*data)) {
+		data.matchFlawsByFingerprint()
+	}
+}
+
+// Flaws in scan A keep their issue ID as the match ID, matched flaws in scan B take on that ID and
+// unmatched flaws in scan B are given a negative match ID so they cannot collide with scan A
+func (data *Data) matchFlawsByFingerprint() {
+	scanAMatched := make(map[int]bool)
+	scanBMatched := make(map[int]bool)
+
+	for _, tier := range flawMatchTiers {
+		scanAFlawIndexesByFingerprint := make(map[string][]int)
+
+		for index, flaw := range data.ScanAReport.Flaws {
+			if scanAMatched[index] {
+				continue
+			}
+
+			if fingerprint := tier.getFingerprint(flaw); len(fingerprint) > 0 {
+				scanAFlawIndexesByFingerprint[fingerprint] = append(scanAFlawIndexesByFingerprint[fingerprint], index)
+			}
+		}
+
+		for index, flaw := range data.ScanBReport.Flaws {
+			if scanBMatched[index] {
+				continue
+			}
+
+			fingerprint := tier.getFingerprint(flaw)
+			candidates := scanAFlawIndexesByFingerprint[fingerprint]
+
+			if len(fingerprint) == 0 || len(candidates) == 0 {
+				continue
+			}
+
+			scanAFlaw := &data.ScanAReport.Flaws[candidates[0]]
+			scanBFlaw := &data.ScanBReport.Flaws[index]
+
+			scanBFlaw.MatchId = scanAFlaw.ID
+			scanAFlaw.MatchConfidence = tier.confidence
+			scanBFlaw.MatchConfidence = tier.confidence
+
+			scanAMatched[candidates[0]] = true
+			scanBMatched[index] = true
+			scanAFlawIndexesByFingerprint[fingerprint] = candidates[1:]
+		}
+	}
+
+	for index, flaw := range data.ScanBReport.Flaws {
+		if !scanBMatched[index] {
+			data.ScanBReport.Flaws[index].MatchId = -flaw.ID
+		}
+	}
+
+	data.MatchedByFingerprint = true
+}
+
+func (data Data) reportFlawMatching() {
+	if !data.MatchedByFingerprint {
+		return
+	}
+
+	var report strings.Builder
+	var lowConfidenceMatches []string
+	matchCounts := make(map[string]int)
+
+	var relocatedMatches = 0
+
+	for _, scanBFlaw := range data.ScanBReport.Flaws {
+		if scanBFlaw.Relocated {
+			relocatedMatches++
+		}
+
+		if len(scanBFlaw.MatchConfidence) == 0 {
+			continue
+		}
+
+		matchCounts[scanBFlaw.MatchConfidence]++
+
+		if scanBFlaw.MatchConfidence == MatchConfidenceLow {
+			lowConfidenceMatches = append(lowConfidenceMatches, fmt.Sprintf("%s %d => %s %d (%s, %s line %d)\n",
+				getFormattedSideString("A"),
+				scanBFlaw.MatchId,
+				getFormattedSideString("B"),
+				scanBFlaw.ID,
+				getFormattedCwe(scanBFlaw.CWE),
+				scanBFlaw.SourceFile,
+				scanBFlaw.LineNumber))
+		}
+	}
+
+	report.WriteString(fmt.Sprintf("%-61s %d\n", "High confidence (module, source file and hashes match):", matchCounts[MatchConfidenceHigh]))
+	report.WriteString(fmt.Sprintf("%-61s %d\n", "Medium confidence (source file and hashes match):", matchCounts[MatchConfidenceMedium]))
+	report.WriteString(fmt.Sprintf("%-61s %d\n", "Low confidence (module, source file and line number match):", matchCounts[MatchConfidenceLow]))
+	report.WriteString(fmt.Sprintf("%-61s %d\n", "Relocated (hashes or function prototype match):", relocatedMatches))
+	report.WriteString(fmt.Sprintf("%-61s %s = %d, %s = %d\n",
+		"Unmatched:",
+		getFormattedSideString("A"),
+		countUnmatchedFlaws(data.ScanAReport),
+		getFormattedSideString("B"),
+		countUnmatchedFlaws(data.ScanBReport)))
+
+	if len(lowConfidenceMatches) > 0 {
+		sort.Strings(lowConfidenceMatches)
+		report.WriteString(color.HiYellowString("\nLow confidence matches should be verified manually:\n"))
+		report.WriteString(strings.Join(lowConfidenceMatches, ""))
+	}
+
+	data.printTitle("Flaw Matching By Fingerprint")
+	data.colorPrintf(report.String())
+}
+
+// Relocated flaws are paired after fingerprint matching, so are matched despite having no confidence
+func countUnmatchedFlaws(report DetailedReport) int {
+	var count = 0
+
+	for _, flaw := range report.Flaws {
+		if len(flaw.MatchConfidence) == 0 && !flaw.Relocated {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+)
+
+func getTestFlaw(id int, module, sourceFile string, lineNumber int, hashes ...string) DetailedReportFlaw {
+	flaw := DetailedReportFlaw{ID: id, MatchId: id, CWE: 89, Module: module, SourceFile: sourceFile, LineNumber: lineNumber}
+
+	if len(hashes) == 3 {
+		flaw.ProcedureHash, flaw.PrototypeHash, flaw.StatementHash = hashes[0], hashes[1], hashes[2]
+	}
+
+	return flaw
+}
+
+func TestMatchFlawsByFingerprintTiers(t *testing.T) {
+	tests := []struct {
+		name               string
+		scanAFlaw          DetailedReportFlaw
+		scanBFlaw          DetailedReportFlaw
+		expectedConfidence string
+	}{
+		{"module, file and hashes match", getTestFlaw(1, "app.jar", "Login.java", 10, "p", "q", "r"), getTestFlaw(2, "app.jar", "Login.java", 20, "p", "q", "r"), MatchConfidenceHigh},
+		{"source file differs only by case and separators", getTestFlaw(1, "app.jar", "com\\Login.java", 10, "p", "q", "r"), getTestFlaw(2, "APP.jar", "/com/login.java", 10, "p", "q", "r"), MatchConfidenceHigh},
+		{"module renamed", getTestFlaw(1, "app-1.0.jar", "Login.java", 10, "p", "q", "r"), getTestFlaw(2, "app-2.0.jar", "Login.java", 10, "p", "q", "r"), MatchConfidenceMedium},
+		{"hashes missing", getTestFlaw(1, "app.jar", "Login.java", 10), getTestFlaw(2, "app.jar", "Login.java", 10), MatchConfidenceLow},
+		{"hashes changed", getTestFlaw(1, "app.jar", "Login.java", 10, "p", "q", "r"), getTestFlaw(2, "app.jar", "Login.java", 10, "p", "q", "s"), MatchConfidenceLow},
+		{"hashes missing and line moved", getTestFlaw(1, "app.jar", "Login.java", 10), getTestFlaw(2, "app.jar", "Login.java", 11), ""},
+		{"different source file", getTestFlaw(1, "app.jar", "Login.java", 10, "p", "q", "r"), getTestFlaw(2, "app.jar", "Logout.java", 10, "p", "q", "r"), ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := Data{
+				ScanAReport: DetailedReport{Flaws: []DetailedReportFlaw{test.scanAFlaw}},
+				ScanBReport: DetailedReport{Flaws: []DetailedReportFlaw{test.scanBFlaw}},
+			}
+
+			data.matchFlaws("fingerprint")
+			scanBFlaw := data.ScanBReport.Flaws[0]
+
+			if scanBFlaw.MatchConfidence != test.expectedConfidence {
+				t.Errorf("got confidence %q, expected %q", scanBFlaw.MatchConfidence, test.expectedConfidence)
+			}
+
+			expectedMatchId := test.scanAFlaw.ID
+
+			if len(test.expectedConfidence) == 0 {
+				expectedMatchId = -test.scanBFlaw.ID
+			}
+
+			if scanBFlaw.MatchId != expectedMatchId {
+				t.Errorf("got match ID %d, expected %d", scanBFlaw.MatchId, expectedMatchId)
+			}
+		})
+	}
+}
+
+func TestMatchFlawsByFingerprintPairsEachFlawOnce(t *testing.T) {
+	data := Data{
+		ScanAReport: DetailedReport{Flaws: []DetailedReportFlaw{getTestFlaw(1, "app.jar", "Login.java", 10), getTestFlaw(2, "app.jar", "Login.java", 10)}},
+		ScanBReport: DetailedReport{Flaws: []DetailedReportFlaw{getTestFlaw(7, "app.jar", "Login.java", 10), getTestFlaw(8, "app.jar", "Login.java", 10), getTestFlaw(9, "app.jar", "Login.java", 10)}},
+	}
+
+	data.matchFlaws("fingerprint")
+
+	var matchIds []int
+
+	for _, flaw := range data.ScanBReport.Flaws {
+		matchIds = append(matchIds, flaw.MatchId)
+	}
+
+	if len(matchIds) != 3 || matchIds[0] != 1 || matchIds[1] != 2 || matchIds[2] != -9 {
+		t.Errorf("got match IDs %v, expected [1 2 -9]", matchIds)
+	}
+}
+
+func TestMatchFlawsModes(t *testing.T) {
+	tests := []struct {
+		name          string
+		matchMode     string
+		scanBAppId    int
+		expectMatched bool
+	}{
+		{"id", "id", 2, false},
+		{"fingerprint", "fingerprint", 1, true},
+		{"auto within one application profile", "auto", 1, false},
+		{"auto across application profiles", "auto", 2, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := Data{
+				ScanAReport: DetailedReport{AppId: 1, Flaws: []DetailedReportFlaw{getTestFlaw(1, "app.jar", "Login.java", 10)}},
+				ScanBReport: DetailedReport{AppId: test.scanBAppId, Flaws: []DetailedReportFlaw{getTestFlaw(2, "app.jar", "Login.java", 10)}},
+			}
+
+			data.matchFlaws(test.matchMode)
+
+			if data.MatchedByFingerprint != test.expectMatched {
+				t.Errorf("got matched by fingerprint = %t, expected %t", data.MatchedByFingerprint, test.expectMatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+type JsonReport struct {
+	ScanA                  JsonReportScan          `json:"scan_a"`
+	ScanB                  JsonReportScan          `json:"scan_b"`
+	FlawsOnlyInA           []int                   `json:"flaws_only_in_a"`
+	FlawsOnlyInB           []int                   `json:"flaws_only_in_b"`
+	FlawStateChanges       []JsonReportFlawChange  `json:"flaw_state_changes"`
+	SelectedModulesOnlyInA []string                `json:"selected_modules_only_in_a"`
+	SelectedModulesOnlyInB []string                `json:"selected_modules_only_in_b"`
+	ChangedFiles           []JsonReportChangedFile `json:"changed_files"`
+	ComponentsOnlyInA      []string                `json:"components_only_in_a"`
+	ComponentsOnlyInB      []string                `json:"components_only_in_b"`
+	CvesOnlyInA            []string                `json:"cves_only_in_a"`
+	CvesOnlyInB            []string                `json:"cves_only_in_b"`
+	Uploads                UploadSimilarity        `json:"uploads"`
+	FlawDifferenceDrivers  map[string]int          `json:"flaw_difference_drivers"`
+	Risk                   JsonReportRisk          `json:"risk"`
+	Owners                 []JsonReportOwner       `json:"owners,omitempty"`
+}
+
+type JsonReportScan struct {
+	AccountId                   int       `json:"account_id"`
+	AppId                       int       `json:"app_id"`
+	AppName                     string    `json:"app_name"`
+	SandboxName                 string    `json:"sandbox_name,omitempty"`
+	BuildId                     int       `json:"build_id"`
+	ScanName                    string    `json:"scan_name"`
+	EngineVersion               string    `json:"engine_version"`
+	PolicyName                  string    `json:"policy_name"`
+	PolicyVersion               int       `json:"policy_version"`
+	PolicyComplianceStatus      string    `json:"policy_compliance_status"`
+	PolicyRulesStatus           string    `json:"policy_rules_status"`
+	Score                       int       `json:"score"`
+	SubmittedDate               time.Time `json:"submitted_date"`
+	PublishedDate               time.Time `json:"published_date"`
+	DurationSeconds             int       `json:"duration_seconds"`
+	FilesUploaded               int       `json:"files_uploaded"`
+	TotalModules                int       `json:"total_modules"`
+	ModulesSelected             int       `json:"modules_selected"`
+	TotalFlaws                  int       `json:"total_flaws"`
+	MitigatedFlaws              int       `json:"mitigated_flaws"`
+	OpenPolicyAffectingFlaws    int       `json:"open_policy_affecting_flaws"`
+	OpenNonPolicyAffectingFlaws int       `json:"open_non_policy_affecting_flaws"`
+}
+
+type JsonReportRisk struct {
+	ScanA    float64            `json:"scan_a"`
+	ScanB    float64            `json:"scan_b"`
+	Delta    float64            `json:"delta"`
+	ByModule map[string]float64 `json:"by_module"`
+	ByCwe    map[int]float64    `json:"by_cwe"`
+}
+
+type JsonReportOwner struct {
+	Owner       string `json:"owner"`
+	NewFlaws    []int  `json:"new_flaws"`
+	ClosedFlaws []int  `json:"closed_flaws"`
+}
+
+type JsonReportFlawChange struct {
+	ID     int    `json:"id"`
+	CWE    int    `json:"cwe"`
+	ScanA  string `json:"scan_a"`
+	ScanB  string `json:"scan_b"`
+	Detail string `json:"detail"`
+}
+
+type JsonReportChangedFile struct {
+	Name     string `json:"name"`
+	ScanAMD5 string `json:"scan_a_md5"`
+	ScanBMD5 string `json:"scan_b_md5"`
+}
+
+func getJsonReportScan(report DetailedReport, prescanFileList PrescanFileList, prescanModuleList PrescanModuleList) JsonReportScan {
+	return JsonReportScan{
+		AccountId:                   report.AccountId,
+		AppId:                       report.AppId,
+		AppName:                     report.AppName,
+		SandboxName:                 report.SandboxName,
+		BuildId:                     report.BuildId,
+		ScanName:                    report.StaticAnalysis.ScanName,
+		EngineVersion:               report.StaticAnalysis.EngineVersion,
+		PolicyName:                  report.PolicyName,
+		PolicyVersion:               report.PolicyVersion,
+		PolicyComplianceStatus:      report.PolicyComplianceStatus,
+		PolicyRulesStatus:           report.PolicyRulesStatus,
+		Score:                       report.StaticAnalysis.Score,
+		SubmittedDate:               report.SubmittedDate,
+		PublishedDate:               report.PublishedDate,
+		DurationSeconds:             int(report.Duration.Seconds()),
+		FilesUploaded:               len(prescanFileList.Files),
+		TotalModules:                len(prescanModuleList.Modules),
+		ModulesSelected:             len(report.StaticAnalysis.Modules),
+		TotalFlaws:                  report.TotalFlaws,
+		MitigatedFlaws:              report.TotalFlaws - report.UnmitigatedFlaws,
+		OpenPolicyAffectingFlaws:    report.getOpenPolicyAffectingFlawCount(),
+		OpenNonPolicyAffectingFlaws: report.getOpenNonPolicyAffectingFlawCount(),
+	}
+}
+
+func getFlawIdsOnlyInThisScan(thisSideReport, otherSideReport DetailedReport) []int {
+	flawIds := []int{}
+
+	for _, flaw := range thisSideReport.Flaws {
+		if !otherSideReport.isFlawInReport(flaw.MatchId) {
+			flawIds = append(flawIds, flaw.ID)
+		}
+	}
+
+	return flawIds
+}
+
+func getComponentNamesOnlyInThisScan(thisSideComponents, otherSideComponents []DetailedReportComponent) []string {
+	names := []string{}
+	otherSideComponentsByName := getComponentsByName(otherSideComponents)
+
+	for _, name := range getSortedComponentNames(getComponentsByName(thisSideComponents)) {
+		if _, found := otherSideComponentsByName[name]; !found {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func getCveIdsOnlyInThisScan(thisSideComponents, otherSideComponents []DetailedReportComponent) []string {
+	cveIds := []string{}
+
+	for _, vulnerability := range getComponentVulnerabilitiesOnlyInThisScan(thisSideComponents, otherSideComponents) {
+		if !isStringInStringArray(vulnerability.vulnerability.CveId, cveIds) {
+			cveIds = append(cveIds, vulnerability.vulnerability.CveId)
+		}
+	}
+
+	return cveIds
+}
+
+func (data Data) getJsonReport() JsonReport {
+	jsonReport := JsonReport{
+		ScanA:                  getJsonReportScan(data.ScanAReport, data.ScanAPrescanFileList, data.ScanAPrescanModuleList),
+		ScanB:                  getJsonReportScan(data.ScanBReport, data.ScanBPrescanFileList, data.ScanBPrescanModuleList),
+		FlawsOnlyInA:           getFlawIdsOnlyInThisScan(data.ScanAReport, data.ScanBReport),
+		FlawsOnlyInB:           getFlawIdsOnlyInThisScan(data.ScanBReport, data.ScanAReport),
+		FlawStateChanges:       []JsonReportFlawChange{},
+		SelectedModulesOnlyInA: getSelectedModuleNamesOnlyInThisScan(data.ScanAReport.StaticAnalysis.Modules, data.ScanBReport.StaticAnalysis.Modules),
+		SelectedModulesOnlyInB: getSelectedModuleNamesOnlyInThisScan(data.ScanBReport.StaticAnalysis.Modules, data.ScanAReport.StaticAnalysis.Modules),
+		ChangedFiles:           []JsonReportChangedFile{},
+		ComponentsOnlyInA:      getComponentNamesOnlyInThisScan(data.ScanAReport.SCA.Components, data.ScanBReport.SCA.Components),
+		ComponentsOnlyInB:      getComponentNamesOnlyInThisScan(data.ScanBReport.SCA.Components, data.ScanAReport.SCA.Components),
+		CvesOnlyInA:            getCveIdsOnlyInThisScan(data.ScanAReport.SCA.Components, data.ScanBReport.SCA.Components),
+		CvesOnlyInB:            getCveIdsOnlyInThisScan(data.ScanBReport.SCA.Components, data.ScanAReport.SCA.Components),
+		Uploads:                data.getUploadSimilarity(),
+		FlawDifferenceDrivers:  data.getFlawDifferenceDriverCounts(),
+		Risk: JsonReportRisk{
+			ScanA:    data.RiskModel.getRiskScore(data.ScanAReport),
+			ScanB:    data.RiskModel.getRiskScore(data.ScanBReport),
+			ByModule: data.getRiskDeltaByModule(),
+			ByCwe:    data.getRiskDeltaByCwe(),
+		},
+	}
+
+	jsonReport.Risk.Delta = jsonReport.Risk.ScanB - jsonReport.Risk.ScanA
+
+	for _, scanAFlaw := range data.ScanAReport.Flaws {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+
+		if scanBFlaw.ID == 0 {
+			continue
+		}
+
+		if scanAFlaw.RemediationStatus != scanBFlaw.RemediationStatus {
+			jsonReport.FlawStateChanges = append(jsonReport.FlawStateChanges, JsonReportFlawChange{scanAFlaw.ID, scanAFlaw.CWE, scanAFlaw.RemediationStatus, scanBFlaw.RemediationStatus, "remediation_status"})
+		}
+
+		if scanAFlaw.MitigationStatus != scanBFlaw.MitigationStatus {
+			jsonReport.FlawStateChanges = append(jsonReport.FlawStateChanges, JsonReportFlawChange{scanAFlaw.ID, scanAFlaw.CWE, scanAFlaw.MitigationStatus, scanBFlaw.MitigationStatus, "mitigation_status"})
+		}
+	}
+
+	if !data.CodeOwners.isEmpty() {
+		differences := data.getFlawDifferencesByOwner()
+
+		for _, owner := range getSortedOwners(differences) {
+			jsonOwner := JsonReportOwner{Owner: owner, NewFlaws: []int{}, ClosedFlaws: []int{}}
+
+			for _, flaw := range differences[owner].newFlaws {
+				jsonOwner.NewFlaws = append(jsonOwner.NewFlaws, flaw.ID)
+			}
+
+			for _, flaw := range differences[owner].closedFlaws {
+				jsonOwner.ClosedFlaws = append(jsonOwner.ClosedFlaws, flaw.ID)
+			}
+
+			jsonReport.Owners = append(jsonReport.Owners, jsonOwner)
+		}
+	}
+
+	for _, changedFile := range data.getChangedFiles() {
+		jsonReport.ChangedFiles = append(jsonReport.ChangedFiles, JsonReportChangedFile{changedFile.Name, changedFile.ScanAMD5, changedFile.ScanBMD5})
+	}
+
+	return jsonReport
+}
+
+func (data Data) printJsonReport() {
+	content, err := json.MarshalIndent(data.getJsonReport(), "", "  ")
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not create the JSON report: %v", err))
+		os.Exit(1)
+	}
+
+	fmt.Println(string(content))
+}
@@ -14,112 +14,349 @@ func main() {
 	fmt.Printf("Scan Compare v%s\nCopyright © Veracode, Inc. 2023. All Rights Reserved.\nThis is an unofficial Veracode product. It does not come with any support or warranty.\n\n", AppVersion)
 	vid := flag.String("vid", "", "Veracode API ID - See https://docs.veracode.com/r/t_create_api_creds")
 	vkey := flag.String("vkey", "", "Veracode API key - See https://docs.veracode.com/r/t_create_api_creds")
-	profile := flag.String("profile", "default", "Veracode credential profile - See https://docs.veracode.com/r/c_httpie_tool")
+	profile := flag.String("profile", "default", "Veracode credential profile - See https://docs.veracode.com/r/c_httpie_tool. If not specified, a profile with a matching \"account_id\" will be used where possible")
 	region := flag.String("region", "", "Veracode Region [commercial, us, european]")
+	profileA := flag.String("profile-a", "", "Veracode credential profile for scan \"A\", if different to scan \"B\"")
+	profileB := flag.String("profile-b", "", "Veracode credential profile for scan \"B\", if different to scan \"A\"")
+	regionA := flag.String("region-a", "", "Veracode Region for scan \"A\", if different to scan \"B\" [commercial, us, european]")
+	regionB := flag.String("region-b", "", "Veracode Region for scan \"B\", if different to scan \"A\" [commercial, us, european]")
+	action := flag.String("action", "compare", "Action to perform [compare, sandboxes, batch, determinism]")
 	scanA := flag.String("a", "", "Veracode Platform URL or build ID for scan \"A\"")
 	scanB := flag.String("b", "", "Veracode Platform URL or build ID for scan \"B\"")
+	match := flag.String("match", "auto", "How to pair flaws between scans [auto, id, fingerprint]. \"auto\" uses fingerprints when the scans are from different regions, accounts or application profiles")
+	callStacks := flag.Bool("callstacks", false, "Compare the call stacks of flaws in both scans which changed line number, module or state. This requires two API calls per flaw")
+	flawId := flag.Int("flaw", 0, "Only report everything known about this flaw (issue ID) in both scans")
+	cwe := flag.String("cwe", "", "Only report flaws with these CWE IDs, e.g. \"79,89\"")
+	minSeverity := flag.Int("min-severity", 0, "Only report flaws of this severity or higher [0-5]")
+	module := flag.String("module", "", "Only report flaws in modules matching this pattern, e.g. \"*.war\"")
+	file := flag.String("file", "", "Only report flaws in source files matching this pattern, e.g. \"src/main/**\"")
+	policyOnly := flag.Bool("policy-only", false, "Only report flaws which affect policy compliance")
+	app := flag.String("app", "", "Veracode Platform URL or application ID for the \"sandboxes\" action")
+	batchFile := flag.String("batch-file", "", "CSV or JSON file of scan pairs for the \"batch\" action. Each scan can be a Veracode Platform URL, build ID, \"policy:<app id>\" or \"sandbox:<app id>:<sandbox name>\"")
+	outputDir := flag.String("output-dir", "reports", "Directory to write reports to for the \"batch\" action")
+	format := flag.String("format", "text", "Report format for the \"compare\" action [text, json, markdown] and the \"batch\" action [text, json]")
+	builds := flag.String("builds", "", "Comma-separated Veracode Platform URLs or build IDs of repeated scans of the same upload for the \"determinism\" action")
+	riskModelFile := flag.String("risk-model", "", "JSON file of weights for the risk score, overriding the defaults. Keys: \"severity_weights\", \"cwe_weights\", \"cwe_top_25_weight\", \"policy_affecting_weight\" and \"exploitability_weights\"")
+	codeOwnersFile := flag.String("codeowners", "", "CODEOWNERS file used to group flaw differences by owning team. Flaw source paths are matched after applying -path-rules")
+	pathRulesFile := flag.String("path-rules", "", "JSON file of rules to normalise flaw source paths and uploaded file names before comparing, e.g. {\"strip_prefixes\": [\"/agent1/work/\"], \"replacements\": [{\"pattern\": \"^/agent\\\\d+/work/\", \"replacement\": \"\"}], \"fold_case\": true}")
+	concurrency := flag.Int("concurrency", 4, "Maximum number of scan pairs to compare at once for the \"batch\" action")
 
 	flag.Parse()
 
-	if !(*region == "" || *region == "commercial" || *region == "us" || *region == "european") {
-		color.HiRed("Error: Invalid region. Must be either \"commercial\", \"us\" or \"european\"")
+	// An empty profile allows a profile to be chosen automatically by account ID
+	if !isFlagPassed("profile") {
+		*profile = ""
+	}
+
+	if !(*match == "auto" || *match == "id" || *match == "fingerprint") {
+		color.HiRed("Error: Invalid value for -match. Must be either \"auto\", \"id\" or \"fingerprint\"")
 		print("\nUsage:\n")
 		flag.PrintDefaults()
 		return
 	}
 
-	if len(*scanA) < 1 && len(*scanB) < 1 {
+	for _, regionToValidate := range []string{*region, *regionA, *regionB} {
+		if !(regionToValidate == "" || regionToValidate == "commercial" || regionToValidate == "us" || regionToValidate == "european") {
+			color.HiRed("Error: Invalid region. Must be either \"commercial\", \"us\" or \"european\"")
+			print("\nUsage:\n")
+			flag.PrintDefaults()
+			return
+		}
+	}
+
+	flawFilter := parseFlawFilter(*cwe, *minSeverity, *module, *file, *policyOnly)
+	riskModel := parseRiskModel(*riskModelFile)
+	pathRules := parsePathRules(*pathRulesFile)
+	codeOwners := parseCodeOwners(*codeOwnersFile, pathRules.FoldCase)
+
+	notifyOfUpdates()
+
+	switch *action {
+	case "compare":
+		runCompare(*vid, *vkey, *profile, *region, *scanA, *scanB, *profileA, *profileB, *regionA, *regionB, *match, *format, flawFilter, riskModel, codeOwners, pathRules, *flawId, *callStacks)
+	case "sandboxes":
+		runSandboxMatrix(*vid, *vkey, *profile, *region, *app, pathRules)
+	case "batch":
+		runBatch(*vid, *vkey, *profile, *region, *batchFile, *outputDir, *format, *concurrency, *match, flawFilter, riskModel, codeOwners, pathRules)
+	case "determinism":
+		runDeterminism(*vid, *vkey, *profile, *region, *builds, *match, pathRules)
+	default:
+		color.HiRed(fmt.Sprintf("Error: Invalid action \"%s\". Must be either \"compare\", \"sandboxes\", \"batch\" or \"determinism\"", *action))
+		print("\nUsage:\n")
+		flag.PrintDefaults()
+	}
+}
+
+func getRegionToUse(region string, urls ...string) string {
+	for _, url := range urls {
+		if region != "" && strings.HasPrefix(url, "https://") && parseRegionFromUrl(url) != region {
+			color.HiRed(fmt.Sprintf("Error: The region from the URL (%s) does not match that specified by the command line (%s)", parseRegionFromUrl(url), region))
+			os.Exit(1)
+		}
+	}
+
+	// Command line region takes precedence
+	if region == "" {
+		return parseRegionFromUrl(urls[0])
+	}
+
+	return region
+}
+
+func getApi(vid, vkey, profile, region string) API {
+	var apiId, apiKey = getCredentials(vid, vkey, profile)
+	var api = API{apiId, apiKey, region}
+
+	api.assertCredentialsWork()
+
+	return api
+}
+
+func isFlagPassed(name string) bool {
+	found := false
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+
+	return found
+}
+
+// A per-side profile is read straight from the credentials file so it is not overridden by -vid/-vkey or environment variables
+func getApiForSide(vid, vkey, profile, sideProfile, region string, accountId int) API {
+	if len(sideProfile) > 0 {
+		var apiId, apiKey = getCredentialsFromProfile(sideProfile)
+		var api = API{apiId, apiKey, region}
+
+		api.assertCredentialsWork()
+
+		return api
+	}
+
+	// Only choose a profile by account ID if the credentials would otherwise come from the default profile
+	if len(profile) > 0 || accountId < 1 || len(vid) > 0 || len(vkey) > 0 || len(os.Getenv("VERACODE_API_KEY_ID")) > 0 || len(os.Getenv("VERACODE_API_KEY_SECRET")) > 0 {
+		return getApi(vid, vkey, profile, region)
+	}
+
+	mappedProfile := getProfileForAccountId(accountId)
+
+	if len(mappedProfile) == 0 {
+		return getApi(vid, vkey, profile, region)
+	}
+
+	apiId, apiKey, err := tryGetCredentialsFromProfile(mappedProfile)
+	var api = API{apiId, apiKey, region}
+
+	if err == nil {
+		err = api.checkCredentialsWork()
+	}
+
+	if err != nil {
+		color.HiYellow(fmt.Sprintf("Warning: The profile [%s] is mapped to account ID %d but could not be used (%s). Falling back to the [default] profile.", mappedProfile, accountId, err))
+		return getApi(vid, vkey, profile, region)
+	}
+
+	fmt.Printf("Using the profile [%s] for account ID %d\n", mappedProfile, accountId)
+
+	return api
+}
+
+// Returns 0 if the account ID is not known, i.e. a build ID was used instead of a URL
+func parseAccountIdFromScanUrl(scanUrl string) int {
+	if !isPlatformURL(scanUrl) {
+		return 0
+	}
+
+	accountId, err := parseAccountIdFromPlatformUrl(scanUrl)
+
+	if err != nil {
+		return 0
+	}
+
+	return accountId
+}
+
+func runCompare(vid, vkey, profile, region, scanA, scanB, profileA, profileB, regionA, regionB, matchMode, format string, flawFilter FlawFilter, riskModel RiskModel, codeOwners CodeOwners, pathRules PathRules, flawId int, callStacks bool) {
+	if len(scanA) < 1 && len(scanB) < 1 {
 		color.HiRed("Error: No Veracode Platform URLs or build IDs specified for scans \"A\" and \"B\". Expected: \"scan_compare -a https://analysiscenter.veracode.com/auth/index.jsp... -b https://analysiscenter.veracode.com/auth/index.jsp...\"")
 		print("\nUsage:\n")
 		flag.PrintDefaults()
 		return
 	}
 
-	if len(*scanA) < 1 {
+	if len(scanA) < 1 {
 		color.HiRed("Error: No Veracode Platform URL or build ID specified for scan \"A\". Expected: \"scan_compare -a https://analysiscenter.veracode.com/auth/index.jsp...\"")
 		print("\nUsage:\n")
 		flag.PrintDefaults()
 		return
 	}
 
-	if len(*scanB) < 1 {
+	if len(scanB) < 1 {
 		color.HiRed("Error: No Veracode Platform URL or build ID specified for scan \"B\". Expected flag \"-b https://analysiscenter.veracode.com/auth/index.jsp...\"")
 		print("\nUsage:\n")
 		flag.PrintDefaults()
 		return
 	}
 
-	if parseRegionFromUrl(*scanA) != parseRegionFromUrl(*scanB) {
-		color.HiRed("Error: Cannot compare between different Veracode regions")
+	if !(format == "text" || format == "json" || format == "markdown") {
+		color.HiRed("Error: Invalid format. Must be either \"text\", \"json\" or \"markdown\"")
 		os.Exit(1)
 	}
 
-	if *region != "" &&
-		((strings.HasPrefix(*scanA, "https://") && parseRegionFromUrl(*scanA) != *region) ||
-			(strings.HasPrefix(*scanB, "https://") && parseRegionFromUrl(*scanB) != *region)) {
-		color.HiRed(fmt.Sprintf("Error: The region from the URL (%s) does not match that specified by the command line (%s)", parseRegionFromUrl(*scanA), *region))
-		os.Exit(1)
+	if len(regionA) == 0 {
+		regionA = region
 	}
 
-	var regionToUse string
-
-	// Command line region takes precedence
-	if *region == "" {
-		regionToUse = parseRegionFromUrl(*scanA)
-	} else {
-		regionToUse = *region
+	if len(regionB) == 0 {
+		regionB = region
 	}
 
-	notifyOfUpdates()
-
-	var apiId, apiKey = getCredentials(*vid, *vkey, *profile)
-	var api = API{apiId, apiKey, regionToUse}
+	scanARegion := getRegionToUse(regionA, scanA)
+	scanBRegion := getRegionToUse(regionB, scanB)
 
-	scanAAppId := parseAppIdFromPlatformUrl(*scanA)
-	scanABuildId := parseBuildIdFromPlatformUrl(*scanA)
-	scanBAppId := parseAppIdFromPlatformUrl(*scanB)
-	scanBBuildId := parseBuildIdFromPlatformUrl(*scanB)
+	scanABuildId := parseScanBuildId(scanA)
+	scanBBuildId := parseScanBuildId(scanB)
 
-	if scanABuildId == scanBBuildId {
+	if scanABuildId == scanBBuildId && scanARegion == scanBRegion {
 		color.HiRed("Error: These are both the same scan")
 		os.Exit(1)
 	}
 
-	api.assertCredentialsWork()
+	scanAAccountId := parseAccountIdFromScanUrl(scanA)
+	scanBAccountId := parseAccountIdFromScanUrl(scanB)
+
+	scanAApi := getApiForSide(vid, vkey, profile, profileA, scanARegion, scanAAccountId)
+	scanBApi := scanAApi
 
-	colorPrintf(fmt.Sprintf("Comparing scan %s against scan %s in the %s region\n",
-		color.HiGreenString("\"A\" (Build id = %d)", scanABuildId),
-		color.HiMagentaString("\"B\" (Build id = %d)", scanBBuildId),
-		api.region))
+	// A build ID carries no account ID, so without a profile for B assume it is in the same account as A
+	isScanBInScanAAccount := len(profileB) == 0 && scanBAccountId == 0 && scanARegion == scanBRegion
+
+	if !isScanBInScanAAccount && (profileA != profileB || scanARegion != scanBRegion || scanAAccountId != scanBAccountId) {
+		scanBApi = getApiForSide(vid, vkey, profile, profileB, scanBRegion, scanBAccountId)
+	}
+
+	// The JSON and Markdown reports are printed on their own so they can be redirected to a file
+	isTextFormat := format == "text"
+
+	if isTextFormat {
+		printComparingScans(scanARegion, scanBRegion, scanABuildId, scanBBuildId)
+	}
 
-	data := api.getData(scanAAppId, scanABuildId, scanBAppId, scanBBuildId)
+	data := getData(scanAApi, scanBApi, scanABuildId, scanBBuildId, matchMode, pathRules)
+
+	// The flaw filter is for the report sections, so is not applied to a single flaw
+	if flawId > 0 {
+		data.reportFlawDetails(flawId)
+		return
+	}
+
+	data.applyFlawFilter(flawFilter)
+	data.RiskModel = riskModel
+	data.CodeOwners = codeOwners
+
+	if isTextFormat {
+		data.reportOnWarnings(scanA, scanB)
+	}
 
-	data.reportOnWarnings(*scanA, *scanB)
 	data.assertPrescanModulesPresent()
+
+	switch format {
+	case "json":
+		data.printJsonReport()
+	case "markdown":
+		fmt.Print(data.getJsonReport().getMarkdown())
+	default:
+		if callStacks {
+			data.fetchCallStacks(scanAApi, scanBApi)
+		}
+
+		data.report()
+	}
+}
+
+func printComparingScans(scanARegion, scanBRegion string, scanABuildId, scanBBuildId int) {
+	if scanARegion == scanBRegion {
+		colorPrintf(fmt.Sprintf("Comparing scan %s against scan %s in the %s region\n",
+			color.HiGreenString("\"A\" (Build id = %d)", scanABuildId),
+			color.HiMagentaString("\"B\" (Build id = %d)", scanBBuildId),
+			scanARegion))
+	} else {
+		colorPrintf(fmt.Sprintf("Comparing scan %s in the %s region against scan %s in the %s region\n",
+			color.HiGreenString("\"A\" (Build id = %d)", scanABuildId),
+			scanARegion,
+			color.HiMagentaString("\"B\" (Build id = %d)", scanBBuildId),
+			scanBRegion))
+	}
+}
+
+func parseScanBuildId(urlOrBuildId string) int {
+	buildId, err := parseBuildIdFromPlatformUrl(urlOrBuildId)
+
+	if err != nil {
+		platformUrlInvalid(urlOrBuildId, err)
+	}
+
+	return buildId
+}
+
+func (data Data) report() {
 	data.reportCommonalities()
-	reportScanDetails(api.region, "A", data.ScanAReport, data.ScanBReport, data.ScanAPrescanFileList, data.ScanBPrescanFileList, data.ScanAPrescanModuleList, data.ScanBPrescanModuleList)
-	reportScanDetails(api.region, "B", data.ScanBReport, data.ScanAReport, data.ScanBPrescanFileList, data.ScanAPrescanFileList, data.ScanBPrescanModuleList, data.ScanAPrescanModuleList)
+	data.reportScanDetails(data.ScanARegion, "A", data.ScanAReport, data.ScanBReport, data.ScanAPrescanFileList, data.ScanBPrescanFileList, data.ScanAPrescanModuleList, data.ScanBPrescanModuleList)
+	data.reportScanDetails(data.ScanBRegion, "B", data.ScanBReport, data.ScanAReport, data.ScanBPrescanFileList, data.ScanAPrescanFileList, data.ScanBPrescanModuleList, data.ScanAPrescanModuleList)
+	data.reportPolicyDifferences()
 	data.reportTopLevelModuleDifferences()
 	data.reportNotSelectedModuleDifferences()
 	data.reportDependencyModuleDifferences()
-	reportDuplicateFiles("A", data.ScanAPrescanFileList)
-	reportDuplicateFiles("B", data.ScanBPrescanFileList)
+	data.reportDuplicateFiles("A", data.ScanAPrescanFileList)
+	data.reportDuplicateFiles("B", data.ScanBPrescanFileList)
 	data.reportModuleDifferences()
+	data.reportFlawMatching()
 	data.reportFlawDifferences()
+	data.reportFlawDifferencesByOwner()
+	data.reportDynamicFlawDifferences()
+	data.reportManualFlawDifferences()
+	data.reportSCADifferences()
+	data.reportRiskDelta()
 	data.reportSummary()
 }
 
 func (data Data) reportOnWarnings(scanAUrl, scanBUrl string) {
 	var report strings.Builder
 
-	if isPlatformURL(scanAUrl) && isPlatformURL(scanBUrl) {
-		if parseAccountIdFromPlatformUrl(scanAUrl) != parseAccountIdFromPlatformUrl(scanBUrl) {
+	scanAPlatformUrl, scanAErr := parsePlatformUrl(scanAUrl)
+	scanBPlatformUrl, scanBErr := parsePlatformUrl(scanBUrl)
+
+	if data.ScanARegion != data.ScanBRegion {
+		report.WriteString("* These scans are from different Veracode regions\n")
+	} else if scanAErr == nil && scanBErr == nil {
+		if scanAPlatformUrl.AccountId != scanBPlatformUrl.AccountId {
 			report.WriteString("* These scans are from different accounts\n")
-		} else if parseAppIdFromPlatformUrl(scanAUrl) != parseAppIdFromPlatformUrl(scanBUrl) {
+		} else if scanAPlatformUrl.AppId != scanBPlatformUrl.AppId {
 			report.WriteString("* These scans are from different application profiles\n")
 		}
 	}
 
+	if data.MatchedByFingerprint {
+		report.WriteString("* Flaws have been matched by their fingerprint (CWE, module, source file and flaw hashes) instead of by issue ID, as issue IDs only line up within an application profile. Flaw IDs shown are from scan A where a match was found\n")
+	}
+
+	if collisions := getFormattedPathRuleCollisions("A", data.ScanAPathRuleCollisions); len(collisions) > 0 {
+		report.WriteString(fmt.Sprintf("* %s\n", collisions))
+	}
+
+	if collisions := getFormattedPathRuleCollisions("B", data.ScanBPathRuleCollisions); len(collisions) > 0 {
+		report.WriteString(fmt.Sprintf("* %s\n", collisions))
+	}
+
+	switch data.getUploadSimilarity().Classification {
+	case UploadsIdentical:
+		report.WriteString("* Every uploaded file is identical (same name and MD5) in both scans. This means any flaw differences are caused by the scan engine or the module selection, not by code changes\n")
+	case UploadsFullyDifferent:
+		report.WriteString("* None of the uploaded files are identical between these scans. This means the flaw differences are likely to be driven by code changes\n")
+	}
+
 	if data.ScanAReport.StaticAnalysis.EngineVersion != data.ScanBReport.StaticAnalysis.EngineVersion {
 		report.WriteString("* The scan engine versions are different. This means there has been one or more deployments of the Veracode scan engine between these scans. This can sometimes explain why new flaws might be reported (due to improved scan coverage), and others are no longer reported (due to a reduction of False Positives)\n")
 	}
@@ -133,14 +370,22 @@ func (data Data) reportOnWarnings(scanAUrl, scanBUrl string) {
 	}
 
 	if report.Len() > 0 {
-		printTitle("Warnings")
-		color.HiYellow(report.String())
+		data.printTitle("Warnings")
+		data.colorPrintf(color.HiYellowString(report.String()))
 	}
 }
 
 func (data Data) reportCommonalities() {
 	var report strings.Builder
 
+	if !data.FlawFilter.isEmpty() {
+		report.WriteString(color.HiYellowString("Flaw filter:        %s\n", data.FlawFilter))
+	}
+
+	if !data.PathRules.isEmpty() {
+		report.WriteString(color.HiYellowString("Path rules:         %s\n", data.PathRules))
+	}
+
 	if data.ScanAReport.AppName == data.ScanBReport.AppName {
 		report.WriteString(fmt.Sprintf("Application:        \"%s\"\n", data.ScanAReport.AppName))
 	}
@@ -180,61 +425,69 @@ func (data Data) reportCommonalities() {
 	}
 
 	if report.Len() > 0 {
-		printTitle("In common with both scans")
-		colorPrintf(report.String())
+		data.printTitle("In common with both scans")
+		data.colorPrintf(report.String())
 	}
 }
 
-func reportScanDetails(region, side string, thisDetailedReport, otherDetailedReport DetailedReport, thisPrescanFileList, otherPrescanFileList PrescanFileList, thisPrescanModuleList, otherPrescanModuleList PrescanModuleList) {
-	colorPrintf(getFormattedSideStringWithMessage(side, fmt.Sprintf("\nScan %s", side)))
-	fmt.Println("\n======")
+func (data Data) reportScanDetails(region, side string, thisDetailedReport, otherDetailedReport DetailedReport, thisPrescanFileList, otherPrescanFileList PrescanFileList, thisPrescanModuleList, otherPrescanModuleList PrescanModuleList) {
+	output := data.getOutput()
+
+	data.colorPrintf(getFormattedSideStringWithMessage(side, fmt.Sprintf("\nScan %s", side)))
+	fmt.Fprintln(output, "\n======")
 
 	if thisDetailedReport.AccountId != otherDetailedReport.AccountId {
-		fmt.Printf("Account ID:         %d\n", thisDetailedReport.AccountId)
+		fmt.Fprintf(output, "Account ID:         %d\n", thisDetailedReport.AccountId)
 	}
 
 	if thisDetailedReport.AppName != otherDetailedReport.AppName {
-		fmt.Printf("Application:        \"%s\"\n", thisDetailedReport.AppName)
+		fmt.Fprintf(output, "Application:        \"%s\"\n", thisDetailedReport.AppName)
 	}
 
 	if thisDetailedReport.SandboxId != otherDetailedReport.SandboxId && len(thisDetailedReport.SandboxName) > 0 {
-		fmt.Printf("Sandbox:            \"%s\"\n", thisDetailedReport.SandboxName)
+		fmt.Fprintf(output, "Sandbox:            \"%s\"\n", thisDetailedReport.SandboxName)
 	}
 
 	if thisDetailedReport.StaticAnalysis.ScanName != otherDetailedReport.StaticAnalysis.ScanName {
-		fmt.Printf("Scan name:          \"%s\"\n", thisDetailedReport.StaticAnalysis.ScanName)
+		fmt.Fprintf(output, "Scan name:          \"%s\"\n", thisDetailedReport.StaticAnalysis.ScanName)
 	}
 
-	fmt.Printf("Review Modules URL: %s\n", thisDetailedReport.getReviewModulesUrl(region))
-	fmt.Printf("Triage Flaws URL:   %s\n", thisDetailedReport.getTriageFlawsUrl(region))
+	fmt.Fprintf(output, "Review Modules URL: %s\n", thisDetailedReport.getReviewModulesUrl(region))
+	fmt.Fprintf(output, "Triage Flaws URL:   %s\n", thisDetailedReport.getTriageFlawsUrl(region))
 
 	if len(thisPrescanFileList.Files) != len(otherPrescanFileList.Files) {
-		fmt.Printf("Files uploaded:     %d\n", len(thisPrescanFileList.Files))
+		fmt.Fprintf(output, "Files uploaded:     %d\n", len(thisPrescanFileList.Files))
 	}
 
 	if len(thisPrescanModuleList.Modules) != len(otherPrescanModuleList.Modules) {
-		fmt.Printf("Total modules:      %d\n", len(thisPrescanModuleList.Modules))
+		fmt.Fprintf(output, "Total modules:      %d\n", len(thisPrescanModuleList.Modules))
 	}
 
 	if len(thisDetailedReport.StaticAnalysis.Modules) != len(otherDetailedReport.StaticAnalysis.Modules) {
-		fmt.Printf("Modules selected:   %d\n", len(thisDetailedReport.StaticAnalysis.Modules))
+		fmt.Fprintf(output, "Modules selected:   %d\n", len(thisDetailedReport.StaticAnalysis.Modules))
 	}
 
 	if thisDetailedReport.StaticAnalysis.EngineVersion != otherDetailedReport.StaticAnalysis.EngineVersion {
-		fmt.Printf("Engine version:     %s\n", thisDetailedReport.StaticAnalysis.EngineVersion)
+		fmt.Fprintf(output, "Engine version:     %s\n", thisDetailedReport.StaticAnalysis.EngineVersion)
 	}
 
-	fmt.Printf("Submitted:          %s (%s ago)\n", thisDetailedReport.SubmittedDate, formatDuration(time.Since(thisDetailedReport.SubmittedDate)))
-	fmt.Printf("Published:          %s (%s ago)\n", thisDetailedReport.PublishedDate, formatDuration(time.Since(thisDetailedReport.PublishedDate)))
-	fmt.Printf("Duration:           %s\n", thisDetailedReport.Duration)
+	fmt.Fprintf(output, "Submitted:          %s (%s ago)\n", thisDetailedReport.SubmittedDate, formatDuration(time.Since(thisDetailedReport.SubmittedDate)))
+	fmt.Fprintf(output, "Published:          %s (%s ago)\n", thisDetailedReport.PublishedDate, formatDuration(time.Since(thisDetailedReport.PublishedDate)))
+	fmt.Fprintf(output, "Duration:           %s\n", thisDetailedReport.Duration)
+
+	if !thisDetailedReport.DynamicAnalysis.SubmittedDate.IsZero() {
+		fmt.Fprintf(output, "Dynamic submitted:  %s (%s ago)\n", thisDetailedReport.DynamicAnalysis.SubmittedDate, formatDuration(time.Since(thisDetailedReport.DynamicAnalysis.SubmittedDate)))
+		fmt.Fprintf(output, "Dynamic published:  %s (%s ago)\n", thisDetailedReport.DynamicAnalysis.PublishedDate, formatDuration(time.Since(thisDetailedReport.DynamicAnalysis.PublishedDate)))
+		fmt.Fprintf(output, "Dynamic duration:   %s\n", thisDetailedReport.DynamicAnalysis.Duration)
+	}
 
 	if !(thisDetailedReport.TotalFlaws == otherDetailedReport.TotalFlaws && thisDetailedReport.UnmitigatedFlaws == otherDetailedReport.UnmitigatedFlaws && thisDetailedReport.getPolicyAffectingFlawCount() == otherDetailedReport.getPolicyAffectingFlawCount() && thisDetailedReport.getOpenNonPolicyAffectingFlawCount() == otherDetailedReport.getOpenNonPolicyAffectingFlawCount()) {
 		flawsFormatted := fmt.Sprintf("Flaws:              %d total, %d mitigated, %d policy affecting, %d open affecting policy, %d open not affecting policy\n", thisDetailedReport.TotalFlaws, thisDetailedReport.TotalFlaws-thisDetailedReport.UnmitigatedFlaws, thisDetailedReport.getPolicyAffectingFlawCount(), thisDetailedReport.getOpenPolicyAffectingFlawCount(), thisDetailedReport.getOpenNonPolicyAffectingFlawCount())
 
 		if thisDetailedReport.TotalFlaws == 0 {
-			color.HiYellow(flawsFormatted)
+			data.colorPrintf(color.HiYellowString(flawsFormatted))
 		} else {
-			fmt.Print(flawsFormatted)
+			fmt.Fprint(output, flawsFormatted)
 		}
 	}
 }
@@ -259,6 +512,8 @@ func (data Data) assertPrescanModulesPresent() {
 func (data Data) reportSummary() {
 	var report strings.Builder
 
+	report.WriteString(data.getUploadSimilaritySummary())
+
 	if data.ScanAReport.SubmittedDate.Before(data.ScanBReport.SubmittedDate) {
 		report.WriteString(fmt.Sprintf("%s was submitted %s after %s\n", getFormattedSideString("B"), formatDuration(data.ScanBReport.SubmittedDate.Sub(data.ScanAReport.SubmittedDate)), getFormattedSideString("A")))
 	} else if data.ScanAReport.SubmittedDate.After(data.ScanBReport.SubmittedDate) {
@@ -271,8 +526,11 @@ func (data Data) reportSummary() {
 		report.WriteString(fmt.Sprintf("%s took longer by %s\n", getFormattedSideString("B"), formatDuration(data.ScanBReport.Duration-data.ScanAReport.Duration)))
 	}
 
+	report.WriteString(data.getOpenFlawSeverityDeltaSummary())
+	report.WriteString(data.getRiskScoreSummary())
+
 	if report.Len() > 0 {
-		printTitle("Summary")
-		colorPrintf(report.String())
+		data.printTitle("Summary")
+		data.colorPrintf(report.String())
 	}
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Manual findings have no hashes or location to fingerprint, so they cannot be paired when
+// issue IDs do not line up, i.e. between different application profiles
+func (data *Data) matchManualFlaws() {
+	if !data.MatchedByFingerprint {
+		return
+	}
+
+	for index, flaw := range data.ScanBReport.ManualFlaws {
+		data.ScanBReport.ManualFlaws[index].MatchId = -flaw.ID
+	}
+}
+
+// Manual penetration test findings are not rediscovered by a rescan, they are carried forward from the
+// latest penetration test, so they are reported separately to static and dynamic flaws
+func (data Data) reportManualFlawDifferences() {
+	if len(data.ScanAReport.ManualFlaws) == 0 && len(data.ScanBReport.ManualFlaws) == 0 {
+		return
+	}
+
+	var report strings.Builder
+
+	for _, scanBFlaw := range getFlawsSortedBySeverity(data.ScanBReport.ManualFlaws) {
+		if !(DetailedReport{Flaws: data.ScanAReport.ManualFlaws}).isFlawInReport(scanBFlaw.MatchId) {
+			report.WriteString(fmt.Sprintf("%s %s %d (%s): %s\n", color.HiRedString("New:               "), getFormattedSeverity(scanBFlaw.Severity), scanBFlaw.ID, getFormattedCwe(scanBFlaw.CWE), scanBFlaw.CategoryName))
+		}
+	}
+
+	for _, scanAFlaw := range getFlawsSortedBySeverity(data.ScanAReport.ManualFlaws) {
+		scanBFlaw := DetailedReport{Flaws: data.ScanBReport.ManualFlaws}.getMatchingFlaw(scanAFlaw)
+
+		if scanBFlaw.ID == 0 {
+			report.WriteString(fmt.Sprintf("%s %s %d (%s): %s\n", color.HiGreenString("No longer reported:"), getFormattedSeverity(scanAFlaw.Severity), scanAFlaw.ID, getFormattedCwe(scanAFlaw.CWE), scanAFlaw.CategoryName))
+			continue
+		}
+
+		if scanAFlaw.isFlawOpen() && !scanBFlaw.isFlawOpen() {
+			report.WriteString(fmt.Sprintf("%s %s %d (%s): %s\n", color.HiGreenString("Closed:            "), getFormattedSeverity(scanAFlaw.Severity), scanAFlaw.ID, getFormattedCwe(scanAFlaw.CWE), scanAFlaw.CategoryName))
+		} else if !scanAFlaw.isFlawOpen() && scanBFlaw.isFlawOpen() {
+			report.WriteString(fmt.Sprintf("%s %s %d (%s): %s\n", color.HiRedString("Reopened:          "), getFormattedSeverity(scanBFlaw.Severity), scanBFlaw.ID, getFormattedCwe(scanBFlaw.CWE), scanBFlaw.CategoryName))
+		}
+
+		if scanAFlaw.Severity != scanBFlaw.Severity {
+			report.WriteString(fmt.Sprintf("%s %s %d (%s): %s: %s, %s: %s\n",
+				"Severity changed:  ",
+				getFormattedSeverity(scanBFlaw.Severity),
+				scanBFlaw.ID,
+				getFormattedCwe(scanBFlaw.CWE),
+				getFormattedSideString("A"),
+				getSeverityName(scanAFlaw.Severity),
+				getFormattedSideString("B"),
+				getSeverityName(scanBFlaw.Severity)))
+		}
+
+		if scanAFlaw.MitigationStatus != scanBFlaw.MitigationStatus {
+			report.WriteString(fmt.Sprintf("%s %s %d (%s): %s: %s, %s: %s\n",
+				"Mitigation changed:",
+				getFormattedSeverity(scanBFlaw.Severity),
+				scanBFlaw.ID,
+				getFormattedCwe(scanBFlaw.CWE),
+				getFormattedSideString("A"),
+				cases.Title(language.English).String(scanAFlaw.MitigationStatus),
+				getFormattedSideString("B"),
+				cases.Title(language.English).String(scanBFlaw.MitigationStatus)))
+		}
+	}
+
+	if report.Len() > 0 && data.MatchedByFingerprint {
+		report.WriteString(color.HiYellowString("\nManual findings cannot be paired between application profiles, so every finding is reported as new or no longer reported\n"))
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Manual Penetration Test Finding Differences")
+		data.colorPrintf(report.String())
+	}
+}
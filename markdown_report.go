@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pipes would otherwise end the table cell
+func escapeMarkdownTableCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+func getMarkdownList(values []string) string {
+	if len(values) == 0 {
+		return "None"
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func getMarkdownIdList(ids []int) string {
+	var values []string
+
+	for _, id := range ids {
+		values = append(values, fmt.Sprintf("%d", id))
+	}
+
+	return getMarkdownList(values)
+}
+
+func writeMarkdownTableRow(report *strings.Builder, cells ...string) {
+	for index, cell := range cells {
+		cells[index] = escapeMarkdownTableCell(cell)
+	}
+
+	report.WriteString(fmt.Sprintf("| %s |\n", strings.Join(cells, " | ")))
+}
+
+func writeMarkdownTableHeader(report *strings.Builder, headings ...string) {
+	writeMarkdownTableRow(report, headings...)
+	report.WriteString(strings.Repeat("|---", len(headings)) + "|\n")
+}
+
+// Renders the same content as the JSON report, e.g. for pasting into a pull request or wiki page
+func (jsonReport JsonReport) getMarkdown() string {
+	var report strings.Builder
+
+	scanA, scanB := jsonReport.ScanA, jsonReport.ScanB
+
+	report.WriteString("# Scan Comparison\n\n## Scans\n\n")
+	writeMarkdownTableHeader(&report, "", "A", "B")
+
+	scanRows := []struct {
+		label  string
+		format func(scan JsonReportScan) string
+	}{
+		{"Application", func(scan JsonReportScan) string { return scan.AppName }},
+		{"Sandbox", func(scan JsonReportScan) string { return scan.SandboxName }},
+		{"Build ID", func(scan JsonReportScan) string { return fmt.Sprintf("%d", scan.BuildId) }},
+		{"Scan name", func(scan JsonReportScan) string { return scan.ScanName }},
+		{"Engine version", func(scan JsonReportScan) string { return scan.EngineVersion }},
+		{"Policy", func(scan JsonReportScan) string {
+			return fmt.Sprintf("\"%s\" version %d", scan.PolicyName, scan.PolicyVersion)
+		}},
+		{"Compliance status", func(scan JsonReportScan) string { return scan.PolicyComplianceStatus }},
+		{"Score", func(scan JsonReportScan) string { return fmt.Sprintf("%d", scan.Score) }},
+		{"Submitted", func(scan JsonReportScan) string { return scan.SubmittedDate.String() }},
+		{"Files uploaded", func(scan JsonReportScan) string { return fmt.Sprintf("%d", scan.FilesUploaded) }},
+		{"Modules selected", func(scan JsonReportScan) string {
+			return fmt.Sprintf("%d of %d", scan.ModulesSelected, scan.TotalModules)
+		}},
+		{"Total flaws", func(scan JsonReportScan) string { return fmt.Sprintf("%d", scan.TotalFlaws) }},
+		{"Open policy affecting flaws", func(scan JsonReportScan) string {
+			return fmt.Sprintf("%d", scan.OpenPolicyAffectingFlaws)
+		}},
+		{"Open non-policy affecting flaws", func(scan JsonReportScan) string {
+			return fmt.Sprintf("%d", scan.OpenNonPolicyAffectingFlaws)
+		}},
+	}
+
+	for _, row := range scanRows {
+		writeMarkdownTableRow(&report, row.label, row.format(scanA), row.format(scanB))
+	}
+
+	report.WriteString("\n## Flaws\n\n")
+	report.WriteString(fmt.Sprintf("- Only in A: %s\n", getMarkdownIdList(jsonReport.FlawsOnlyInA)))
+	report.WriteString(fmt.Sprintf("- Only in B: %s\n", getMarkdownIdList(jsonReport.FlawsOnlyInB)))
+
+	if len(jsonReport.FlawStateChanges) > 0 {
+		report.WriteString("\n")
+		writeMarkdownTableHeader(&report, "Flaw", "CWE", "A", "B", "Change")
+
+		for _, change := range jsonReport.FlawStateChanges {
+			writeMarkdownTableRow(&report, fmt.Sprintf("%d", change.ID), fmt.Sprintf("%d", change.CWE), change.ScanA, change.ScanB, strings.ReplaceAll(change.Detail, "_", " "))
+		}
+	}
+
+	report.WriteString("\n## Modules\n\n")
+	report.WriteString(fmt.Sprintf("- Uploads: %s (%d matching files, A: %d, B: %d)\n", jsonReport.Uploads.Classification, jsonReport.Uploads.MatchingFiles, jsonReport.Uploads.ScanAFiles, jsonReport.Uploads.ScanBFiles))
+	report.WriteString(fmt.Sprintf("- Selected modules only in A: %s\n", getMarkdownList(jsonReport.SelectedModulesOnlyInA)))
+	report.WriteString(fmt.Sprintf("- Selected modules only in B: %s\n", getMarkdownList(jsonReport.SelectedModulesOnlyInB)))
+
+	if len(jsonReport.ChangedFiles) > 0 {
+		report.WriteString("\n")
+		writeMarkdownTableHeader(&report, "Changed file", "A MD5", "B MD5")
+
+		for _, changedFile := range jsonReport.ChangedFiles {
+			writeMarkdownTableRow(&report, changedFile.Name, changedFile.ScanAMD5, changedFile.ScanBMD5)
+		}
+	}
+
+	report.WriteString("\n## Third-Party Components\n\n")
+	report.WriteString(fmt.Sprintf("- Components only in A: %s\n", getMarkdownList(jsonReport.ComponentsOnlyInA)))
+	report.WriteString(fmt.Sprintf("- Components only in B: %s\n", getMarkdownList(jsonReport.ComponentsOnlyInB)))
+	report.WriteString(fmt.Sprintf("- CVEs only in A: %s\n", getMarkdownList(jsonReport.CvesOnlyInA)))
+	report.WriteString(fmt.Sprintf("- CVEs only in B: %s\n", getMarkdownList(jsonReport.CvesOnlyInB)))
+
+	report.WriteString("\n## Risk\n\n")
+	report.WriteString(fmt.Sprintf("- A: %.1f, B: %.1f (%+.1f)\n", jsonReport.Risk.ScanA, jsonReport.Risk.ScanB, jsonReport.Risk.Delta))
+
+	if len(jsonReport.Risk.ByModule) > 0 {
+		var modules []string
+
+		for module := range jsonReport.Risk.ByModule {
+			modules = append(modules, module)
+		}
+
+		sort.Strings(modules)
+		report.WriteString("\n")
+		writeMarkdownTableHeader(&report, "Module", "Risk delta")
+
+		for _, module := range modules {
+			writeMarkdownTableRow(&report, module, fmt.Sprintf("%+.1f", jsonReport.Risk.ByModule[module]))
+		}
+	}
+
+	if len(jsonReport.Risk.ByCwe) > 0 {
+		var cwes []int
+
+		for cwe := range jsonReport.Risk.ByCwe {
+			cwes = append(cwes, cwe)
+		}
+
+		sort.Ints(cwes)
+		report.WriteString("\n")
+		writeMarkdownTableHeader(&report, "CWE", "Risk delta")
+
+		for _, cwe := range cwes {
+			writeMarkdownTableRow(&report, fmt.Sprintf("CWE-%d", cwe), fmt.Sprintf("%+.1f", jsonReport.Risk.ByCwe[cwe]))
+		}
+	}
+
+	if len(jsonReport.Owners) > 0 {
+		report.WriteString("\n## Flaw Differences By Owner\n\n")
+		writeMarkdownTableHeader(&report, "Owner", "New", "Closed", "New flaws", "Closed flaws")
+
+		for _, owner := range jsonReport.Owners {
+			writeMarkdownTableRow(&report,
+				owner.Owner,
+				fmt.Sprintf("%d", len(owner.NewFlaws)),
+				fmt.Sprintf("%d", len(owner.ClosedFlaws)),
+				getMarkdownIdList(owner.NewFlaws),
+				getMarkdownIdList(owner.ClosedFlaws))
+		}
+	}
+
+	return report.String()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJsonReportGetMarkdown(t *testing.T) {
+	jsonReport := JsonReport{
+		FlawsOnlyInA:           []int{1, 2},
+		SelectedModulesOnlyInB: []string{"a|b.jar"},
+		Owners: []JsonReportOwner{
+			{Owner: "@backend", NewFlaws: []int{7}, ClosedFlaws: []int{1, 2}},
+			{Owner: unownedFlawOwner, NewFlaws: []int{}, ClosedFlaws: []int{}},
+		},
+	}
+
+	markdown := jsonReport.getMarkdown()
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"flaws only in A", "- Only in A: 1, 2\n"},
+		{"flaws only in B", "- Only in B: None\n"},
+		{"modules are not escaped outside tables", "- Selected modules only in B: a|b.jar\n"},
+		{"owner heading", "## Flaw Differences By Owner\n"},
+		{"owner", "| @backend | 1 | 2 | 7 | 1, 2 |\n"},
+		{"unowned", "| (unowned) | 0 | 0 | None | None |\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !strings.Contains(markdown, test.expected) {
+				t.Errorf("expected the report to contain %q", test.expected)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdownTableCell(t *testing.T) {
+	if result := escapeMarkdownTableCell("a|b"); result != "a\\|b" {
+		t.Errorf("got %q, expected %q", result, "a\\|b")
+	}
+}
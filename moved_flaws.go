@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Each key is tried in turn to pair flaws that are only in one scan because they were relocated
+var flawRelocationKeys = []func(flaw DetailedReportFlaw) string{
+	func(flaw DetailedReportFlaw) string {
+		if len(flaw.StatementHash) == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf("%d|%s", flaw.CWE, flaw.StatementHash)
+	},
+	// The function moved to a different file or module
+	func(flaw DetailedReportFlaw) string {
+		if len(flaw.FunctionPrototype) == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf("%d|%s|%d", flaw.CWE, flaw.FunctionPrototype, flaw.FunctionLocation)
+	},
+}
+
+func (flaw DetailedReportFlaw) getFormattedLocation() string {
+	var location = fmt.Sprintf("\"%s\" %s%s:%d", flaw.Module, flaw.SourceFilePath, flaw.SourceFile, flaw.LineNumber)
+
+	if len(flaw.FunctionPrototype) > 0 {
+		location = fmt.Sprintf("%s in %s", location, flaw.FunctionPrototype)
+	}
+
+	return location
+}
+
+func (flaw DetailedReportFlaw) hasMovedFrom(otherFlaw DetailedReportFlaw) bool {
+	return flaw.Module != otherFlaw.Module ||
+		flaw.SourceFilePath != otherFlaw.SourceFilePath ||
+		flaw.SourceFile != otherFlaw.SourceFile ||
+		flaw.FunctionPrototype != otherFlaw.FunctionPrototype
+}
+
+// Pairs flaws which would otherwise be reported as closed in one scan and new in the other. Issue IDs
+// already follow relocated flaws within an application profile, so "-match id" pairs by ID alone
+func (data *Data) matchRelocatedFlaws() {
+	if !data.MatchedByFingerprint {
+		return
+	}
+
+	for _, getRelocationKey := range flawRelocationKeys {
+		scanAFlawIndexesByKey := make(map[string][]int)
+
+		for index, flaw := range data.ScanAReport.Flaws {
+			if data.ScanBReport.isFlawInReport(flaw.MatchId) {
+				continue
+			}
+
+			if key := getRelocationKey(flaw); len(key) > 0 {
+				scanAFlawIndexesByKey[key] = append(scanAFlawIndexesByKey[key], index)
+			}
+		}
+
+		for index, flaw := range data.ScanBReport.Flaws {
+			if data.ScanAReport.isFlawInReport(flaw.MatchId) {
+				continue
+			}
+
+			key := getRelocationKey(flaw)
+			candidates := scanAFlawIndexesByKey[key]
+
+			if len(key) == 0 || len(candidates) == 0 {
+				continue
+			}
+
+			scanAFlaw := &data.ScanAReport.Flaws[candidates[0]]
+			scanBFlaw := &data.ScanBReport.Flaws[index]
+
+			if !scanBFlaw.hasMovedFrom(*scanAFlaw) {
+				continue
+			}
+
+			scanBFlaw.MatchId = scanAFlaw.MatchId
+			scanAFlaw.Relocated = true
+			scanBFlaw.Relocated = true
+
+			scanAFlawIndexesByKey[key] = candidates[1:]
+		}
+	}
+}
+
+func (data Data) reportMovedFlaws() {
+	var report strings.Builder
+
+	for _, scanAFlaw := range getFlawsSortedBySeverity(data.ScanAReport.Flaws) {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+
+		if scanBFlaw.ID == 0 || !scanBFlaw.hasMovedFrom(scanAFlaw) {
+			continue
+		}
+
+		var formattedIds = fmt.Sprintf("%d", scanAFlaw.ID)
+		var matchedBy = "same issue ID"
+
+		if scanBFlaw.ID != scanAFlaw.ID {
+			formattedIds = fmt.Sprintf("%s %d => %s %d", getFormattedSideString("A"), scanAFlaw.ID, getFormattedSideString("B"), scanBFlaw.ID)
+		}
+
+		if scanBFlaw.Relocated {
+			matchedBy = "matched by hashes or function prototype"
+		} else if len(scanBFlaw.MatchConfidence) > 0 {
+			matchedBy = fmt.Sprintf("%s confidence fingerprint match", strings.ToLower(scanBFlaw.MatchConfidence))
+		}
+
+		report.WriteString(fmt.Sprintf("%s %s (%s, %s):\n  %s: %s\n  %s: %s\n",
+			getFormattedSeverity(scanAFlaw.Severity),
+			formattedIds,
+			getFormattedCwe(scanAFlaw.CWE),
+			matchedBy,
+			getFormattedSideString("A"),
+			scanAFlaw.getFormattedLocation(),
+			getFormattedSideString("B"),
+			scanBFlaw.getFormattedLocation()))
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Moved Flaws")
+		data.colorPrintf(report.String())
+	}
+}
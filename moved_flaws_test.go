@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMatchRelocatedFlaws(t *testing.T) {
+	tests := []struct {
+		name                 string
+		scanAFlaw            DetailedReportFlaw
+		scanBFlaw            DetailedReportFlaw
+		matchedByFingerprint bool
+		expectRelocated      bool
+	}{
+		{"statement hash matches in another file",
+			DetailedReportFlaw{ID: 1, CWE: 89, SourceFile: "Login.java", StatementHash: "s"},
+			DetailedReportFlaw{ID: 2, CWE: 89, SourceFile: "Auth.java", StatementHash: "s"},
+			true, true},
+		{"function prototype matches in another module",
+			DetailedReportFlaw{ID: 1, CWE: 89, Module: "a.jar", FunctionPrototype: "void login()", FunctionLocation: 5},
+			DetailedReportFlaw{ID: 2, CWE: 89, Module: "b.jar", FunctionPrototype: "void login()", FunctionLocation: 5},
+			true, true},
+		{"different CWE",
+			DetailedReportFlaw{ID: 1, CWE: 89, SourceFile: "Login.java", StatementHash: "s"},
+			DetailedReportFlaw{ID: 2, CWE: 79, SourceFile: "Auth.java", StatementHash: "s"},
+			true, false},
+		{"same location",
+			DetailedReportFlaw{ID: 1, CWE: 89, SourceFile: "Login.java", StatementHash: "s"},
+			DetailedReportFlaw{ID: 2, CWE: 89, SourceFile: "Login.java", StatementHash: "s"},
+			true, false},
+		{"no hashes or function prototype",
+			DetailedReportFlaw{ID: 1, CWE: 89, SourceFile: "Login.java"},
+			DetailedReportFlaw{ID: 2, CWE: 89, SourceFile: "Auth.java"},
+			true, false},
+		{"matched by issue ID",
+			DetailedReportFlaw{ID: 1, CWE: 89, SourceFile: "Login.java", StatementHash: "s"},
+			DetailedReportFlaw{ID: 2, CWE: 89, SourceFile: "Auth.java", StatementHash: "s"},
+			false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.scanAFlaw.MatchId = test.scanAFlaw.ID
+			test.scanBFlaw.MatchId = test.scanBFlaw.ID
+
+			if test.matchedByFingerprint {
+				test.scanBFlaw.MatchId = -test.scanBFlaw.ID
+			}
+
+			data := Data{
+				ScanAReport:          DetailedReport{Flaws: []DetailedReportFlaw{test.scanAFlaw}},
+				ScanBReport:          DetailedReport{Flaws: []DetailedReportFlaw{test.scanBFlaw}},
+				MatchedByFingerprint: test.matchedByFingerprint,
+			}
+
+			data.matchRelocatedFlaws()
+			scanAFlaw, scanBFlaw := data.ScanAReport.Flaws[0], data.ScanBReport.Flaws[0]
+
+			if scanAFlaw.Relocated != test.expectRelocated || scanBFlaw.Relocated != test.expectRelocated {
+				t.Fatalf("got relocated A = %t, B = %t, expected %t", scanAFlaw.Relocated, scanBFlaw.Relocated, test.expectRelocated)
+			}
+
+			if test.expectRelocated && scanBFlaw.MatchId != scanAFlaw.MatchId {
+				t.Errorf("got B match ID %d, expected %d", scanBFlaw.MatchId, scanAFlaw.MatchId)
+			}
+
+			if test.expectRelocated && (countUnmatchedFlaws(data.ScanAReport) != 0 || countUnmatchedFlaws(data.ScanBReport) != 0) {
+				t.Errorf("relocated flaws were counted as unmatched")
+			}
+		})
+	}
+}
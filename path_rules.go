@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type PathReplacement struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	regex       *regexp.Regexp
+}
+
+// Build agents embed different absolute paths in debug information, e.g. "/agent1/work/src/..." versus
+// "/agent7/work/src/...", so paths are rewritten before any comparison. The first matching prefix is
+// stripped, then each replacement is applied in turn, then the path is lower-cased if folding case
+type PathRules struct {
+	StripPrefixes []string          `json:"strip_prefixes"`
+	Replacements  []PathReplacement `json:"replacements"`
+	FoldCase      bool              `json:"fold_case"`
+}
+
+func parsePathRules(pathRulesFile string) PathRules {
+	rules := PathRules{}
+
+	if len(pathRulesFile) == 0 {
+		return rules
+	}
+
+	content, err := os.ReadFile(pathRulesFile)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not read the path rules file \"%s\"", pathRulesFile))
+		os.Exit(1)
+	}
+
+	if err := json.Unmarshal(content, &rules); err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not parse the path rules file \"%s\": %v", pathRulesFile, err))
+		os.Exit(1)
+	}
+
+	for index, replacement := range rules.Replacements {
+		regex, err := regexp.Compile(replacement.Pattern)
+
+		if err != nil {
+			color.HiRed(fmt.Sprintf("Error: Invalid regular expression \"%s\" in the path rules file \"%s\": %v", replacement.Pattern, pathRulesFile, err))
+			os.Exit(1)
+		}
+
+		rules.Replacements[index].regex = regex
+	}
+
+	return rules
+}
+
+func (rules PathRules) isEmpty() bool {
+	return len(rules.StripPrefixes) == 0 && len(rules.Replacements) == 0 && !rules.FoldCase
+}
+
+func (rules PathRules) normalise(path string) string {
+	if rules.isEmpty() {
+		return path
+	}
+
+	path = strings.ReplaceAll(path, "\\", "/")
+
+	for _, prefix := range rules.StripPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+
+	for _, replacement := range rules.Replacements {
+		path = replacement.regex.ReplaceAllString(path, replacement.Replacement)
+	}
+
+	if rules.FoldCase {
+		path = strings.ToLower(path)
+	}
+
+	return path
+}
+
+// The flaw's directory and file name are normalised together so a prefix can span both
+func (rules PathRules) normaliseFlawPaths(report *DetailedReport) {
+	if rules.isEmpty() {
+		return
+	}
+
+	for index, flaw := range report.Flaws {
+		path := rules.normalise(flaw.SourceFilePath + flaw.SourceFile)
+		separatorIndex := strings.LastIndex(path, "/")
+
+		report.Flaws[index].SourceFilePath = path[:separatorIndex+1]
+		report.Flaws[index].SourceFile = path[separatorIndex+1:]
+	}
+}
+
+// Call stack frames are normalised the same way as flaws so a frame is not reported as changed just because the build path moved
+func (rules PathRules) normaliseCallStackPaths(callStacks *CallStacks) {
+	if rules.isEmpty() {
+		return
+	}
+
+	for stackIndex, callStack := range callStacks.CallStacks {
+		for callIndex, call := range callStack.Calls {
+			path := rules.normalise(call.FilePath + call.FileName)
+			separatorIndex := strings.LastIndex(path, "/")
+
+			callStacks.CallStacks[stackIndex].Calls[callIndex].FilePath = path[:separatorIndex+1]
+			callStacks.CallStacks[stackIndex].Calls[callIndex].FileName = path[separatorIndex+1:]
+		}
+	}
+}
+
+// Returns the original names of any uploaded files which now share a normalised name, keyed by that name
+func (rules PathRules) normaliseFileNames(fileList *PrescanFileList) map[string][]string {
+	collisions := make(map[string][]string)
+
+	if rules.isEmpty() {
+		return collisions
+	}
+
+	originalNames := make(map[string][]string)
+
+	for index, file := range fileList.Files {
+		fileList.Files[index].Name = rules.normalise(file.Name)
+
+		if !isStringInStringArray(file.Name, originalNames[fileList.Files[index].Name]) {
+			originalNames[fileList.Files[index].Name] = append(originalNames[fileList.Files[index].Name], file.Name)
+		}
+	}
+
+	for name, names := range originalNames {
+		if len(names) > 1 {
+			sort.Strings(names)
+			collisions[name] = names
+		}
+	}
+
+	sort.Slice(fileList.Files, func(i, j int) bool {
+		return fileList.Files[i].Name < fileList.Files[j].Name
+	})
+
+	return collisions
+}
+
+func (data *Data) applyPathRules(rules PathRules) {
+	rules.normaliseFlawPaths(&data.ScanAReport)
+	rules.normaliseFlawPaths(&data.ScanBReport)
+	data.ScanAPathRuleCollisions = rules.normaliseFileNames(&data.ScanAPrescanFileList)
+	data.ScanBPathRuleCollisions = rules.normaliseFileNames(&data.ScanBPrescanFileList)
+	data.PathRules = rules
+}
+
+// Files which only share a name because of the path rules would otherwise be treated as duplicates, or paired with the other scan
+func getFormattedPathRuleCollisions(side string, collisions map[string][]string) string {
+	if len(collisions) == 0 {
+		return ""
+	}
+
+	var names []string
+	var fileCount = 0
+
+	for name := range collisions {
+		names = append(names, name)
+		fileCount += len(collisions[name])
+	}
+
+	sort.Strings(names)
+
+	var example = fmt.Sprintf("\"%s\" from \"%s\"", names[0], strings.Join(collisions[names[0]], "\", \""))
+
+	return fmt.Sprintf("The path rules map %d different uploaded files in scan %s onto %d names, e.g. %s. These files are treated as duplicates and may be paired with the wrong file in the other scan", fileCount, side, len(names), example)
+}
+
+func (rules PathRules) String() string {
+	var parts []string
+
+	for _, prefix := range rules.StripPrefixes {
+		parts = append(parts, fmt.Sprintf("strip \"%s\"", prefix))
+	}
+
+	for _, replacement := range rules.Replacements {
+		parts = append(parts, fmt.Sprintf("replace \"%s\" with \"%s\"", replacement.Pattern, replacement.Replacement))
+	}
+
+	if rules.FoldCase {
+		parts = append(parts, "ignore case")
+	}
+
+	return strings.Join(parts, "; ")
+}
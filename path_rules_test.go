@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func getTestPathRules(t *testing.T, content string) PathRules {
+	path := filepath.Join(t.TempDir(), "path_rules.json")
+
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return parsePathRules(path)
+}
+
+func TestPathRulesNormalise(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    string
+		path     string
+		expected string
+	}{
+		{"no rules", `{}`, "C:\\agent1\\work\\Login.java", "C:\\agent1\\work\\Login.java"},
+		{"strip prefix", `{"strip_prefixes": ["/agent1/work/"]}`, "/agent1/work/src/Login.java", "src/Login.java"},
+		{"only the first matching prefix is stripped", `{"strip_prefixes": ["/agent1/", "/agent1/work/"]}`, "/agent1/work/src/Login.java", "work/src/Login.java"},
+		{"backslashes", `{"strip_prefixes": ["C:/agent1/"]}`, "C:\\agent1\\src\\Login.java", "src/Login.java"},
+		{"replacement", `{"replacements": [{"pattern": "^/agent\\d+/work/", "replacement": ""}]}`, "/agent7/work/src/Login.java", "src/Login.java"},
+		{"replacements in turn", `{"replacements": [{"pattern": "^/build/", "replacement": "/src/"}, {"pattern": "^/src/", "replacement": ""}]}`, "/build/Login.java", "Login.java"},
+		{"fold case", `{"fold_case": true}`, "Src/Login.java", "src/login.java"},
+		{"fold case after stripping", `{"strip_prefixes": ["/Agent1/"], "fold_case": true}`, "/Agent1/Src/Login.java", "src/login.java"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := getTestPathRules(t, test.rules).normalise(test.path); result != test.expected {
+				t.Errorf("got %q, expected %q", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestPathRulesNormaliseFlawPaths(t *testing.T) {
+	rules := getTestPathRules(t, `{"strip_prefixes": ["/agent1/work/"]}`)
+	report := DetailedReport{Flaws: []DetailedReportFlaw{
+		{SourceFilePath: "/agent1/", SourceFile: "work/src/Login.java"},
+		{SourceFilePath: "/agent1/work/", SourceFile: "Login.java"},
+	}}
+
+	rules.normaliseFlawPaths(&report)
+
+	tests := []struct {
+		sourceFilePath string
+		sourceFile     string
+	}{
+		{"src/", "Login.java"},
+		{"", "Login.java"},
+	}
+
+	for index, test := range tests {
+		if flaw := report.Flaws[index]; flaw.SourceFilePath != test.sourceFilePath || flaw.SourceFile != test.sourceFile {
+			t.Errorf("flaw %d: got %q %q, expected %q %q", index, flaw.SourceFilePath, flaw.SourceFile, test.sourceFilePath, test.sourceFile)
+		}
+	}
+}
+
+func TestPathRulesNormaliseCallStackPaths(t *testing.T) {
+	rules := getTestPathRules(t, `{"strip_prefixes": ["/agent1/work/"], "fold_case": true}`)
+	callStacks := CallStacks{CallStacks: []CallStack{{Calls: []CallStackCall{{FilePath: "/agent1/work/Src/", FileName: "Login.java"}}}}}
+
+	rules.normaliseCallStackPaths(&callStacks)
+
+	if call := callStacks.CallStacks[0].Calls[0]; call.FilePath != "src/" || call.FileName != "login.java" {
+		t.Errorf("got %q %q, expected %q %q", call.FilePath, call.FileName, "src/", "login.java")
+	}
+}
+
+func TestPathRulesNormaliseFileNames(t *testing.T) {
+	tests := []struct {
+		name               string
+		rules              string
+		expectedNames      []string
+		expectedCollisions map[string][]string
+	}{
+		{"no rules", `{}`, []string{"Login.java", "login.java", "b.jar"}, map[string][]string{}},
+		{"no collisions", `{"strip_prefixes": ["b"]}`, []string{".jar", "Login.java", "login.java"}, map[string][]string{}},
+		{"fold case collides", `{"fold_case": true}`, []string{"b.jar", "login.java", "login.java"}, map[string][]string{"login.java": {"Login.java", "login.java"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileList := PrescanFileList{Files: []PrescanFile{{Name: "Login.java"}, {Name: "login.java"}, {Name: "b.jar"}}}
+			collisions := getTestPathRules(t, test.rules).normaliseFileNames(&fileList)
+
+			var names []string
+
+			for _, file := range fileList.Files {
+				names = append(names, file.Name)
+			}
+
+			if !reflect.DeepEqual(names, test.expectedNames) {
+				t.Errorf("got names %v, expected %v", names, test.expectedNames)
+			}
+
+			if !reflect.DeepEqual(collisions, test.expectedCollisions) {
+				t.Errorf("got collisions %v, expected %v", collisions, test.expectedCollisions)
+			}
+		})
+	}
+}
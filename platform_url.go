@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -9,142 +10,191 @@ import (
 	"github.com/fatih/color"
 )
 
+// Pages which carry build context, i.e. account:app:build:analysis:unit::::sandbox
 var supportedPages = []string{
 	"ReviewResultsStaticFlaws",
 	"ReviewResultsAllFlaws",
+	"ReviewResultsDynamicFlaws",
+	"ReviewResultsManualFlaws",
 	"AnalyzeAppModuleList",
-	"StaticOverview",
 	"AnalyzeAppSourceFiles",
+	"StaticOverview",
+	"DynamicOverview",
 	"ViewReportsResultSummary",
 	"ViewReportsDetailedReport"}
 
-func platformUrlInvalid(url string) {
+// Pages which only carry application context, i.e. account:app
+var supportedApplicationPages = []string{
+	"HomeAppProfile"}
+
+var platformHosts = map[string]string{
+	"analysiscenter.veracode.com": "commercial",
+	"analysiscenter.veracode.us":  "us",
+	"analysiscenter.veracode.eu":  "european",
+}
+
+type PlatformUrl struct {
+	Region     string
+	Page       string
+	AccountId  int
+	AppId      int
+	BuildId    int
+	AnalysisId int
+	UnitId     int
+	SandboxId  int
+}
+
+func platformUrlInvalid(url string, err error) {
 	color.HiRed(
-		fmt.Sprintf("%s is not a valid or supported Veracode Platform URL.\nThis tool requires a URL to one of the following Veracode Platform pages: %s",
+		fmt.Sprintf("Error: %s is not a valid or supported Veracode Platform URL: %s.\nThis tool requires a URL to one of the following Veracode Platform pages: %s",
 			url,
-			strings.Join(supportedPages, ", ")))
+			err,
+			strings.Join(append(append([]string{}, supportedPages...), supportedApplicationPages...), ", ")))
 	os.Exit(1)
 }
 
-func isPlatformURL(url string) bool {
-	return strings.HasPrefix(url, "https://analysiscenter.veracode.com/auth/index.jsp") ||
-		strings.HasPrefix(url, "https://analysiscenter.veracode.us/auth/index.jsp") ||
-		strings.HasPrefix(url, "https://analysiscenter.veracode.eu/auth/index.jsp")
-}
+func isPlatformURL(platformUrl string) bool {
+	parsedUrl, err := url.Parse(strings.TrimSpace(platformUrl))
 
-func isParseableURL(urlFragment string) bool {
-	for _, page := range supportedPages {
-		if strings.HasPrefix(urlFragment, page) {
-			return true
-		}
+	if err != nil {
+		return false
 	}
-	return false
+
+	_, isPlatformHost := platformHosts[strings.ToLower(parsedUrl.Host)]
+	return parsedUrl.Scheme == "https" && isPlatformHost
 }
 
-func parseRegionFromUrl(url string) string {
-	if strings.HasPrefix(url, "https://analysiscenter.veracode.us") {
-		return "us"
+func parsePlatformUrl(platformUrl string) (PlatformUrl, error) {
+	parsedUrl, err := url.Parse(strings.TrimSpace(platformUrl))
+
+	if err != nil {
+		return PlatformUrl{}, fmt.Errorf("the URL could not be parsed")
 	}
 
-	if strings.HasPrefix(url, "https://analysiscenter.veracode.eu") {
-		return "european"
+	region, isPlatformHost := platformHosts[strings.ToLower(parsedUrl.Host)]
+
+	if parsedUrl.Scheme != "https" || !isPlatformHost {
+		return PlatformUrl{}, fmt.Errorf("the URL is not for the Veracode Platform")
 	}
 
-	return "commercial"
-}
+	if !strings.HasPrefix(parsedUrl.Path, "/auth/") {
+		return PlatformUrl{}, fmt.Errorf("the URL is not for a signed-in Veracode Platform page")
+	}
 
-func parseBaseUrlFromRegion(region string) string {
-	if region == "us" {
-		return "https://analysiscenter.veracode.us"
+	// The fragment is already percent-decoded. Ignore any trailing parameters
+	fragment := strings.TrimSpace(parsedUrl.Fragment)
+
+	if index := strings.IndexAny(fragment, "?&"); index >= 0 {
+		fragment = fragment[:index]
 	}
 
-	if region == "european" {
-		return "https://analysiscenter.veracode.eu"
+	if len(fragment) == 0 {
+		return PlatformUrl{}, fmt.Errorf("the URL does not contain a page reference after the \"#\"")
 	}
 
-	return "https://analysiscenter.veracode.com"
-}
+	parts := strings.Split(fragment, ":")
+	result := PlatformUrl{Region: region, Page: parts[0]}
 
-func parseAccountIdFromPlatformUrl(urlOrAccountId string) int {
-	accountId, err := strconv.Atoi(urlOrAccountId)
+	hasBuildContext := isStringInStringArray(result.Page, supportedPages)
 
-	if err == nil {
-		return accountId
+	if !hasBuildContext && !isStringInStringArray(result.Page, supportedApplicationPages) {
+		return PlatformUrl{}, fmt.Errorf("the page \"%s\" is not supported", result.Page)
 	}
 
-	if !isPlatformURL(urlOrAccountId) {
-		platformUrlInvalid(urlOrAccountId)
+	fields := []struct {
+		name   string
+		index  int
+		target *int
+	}{
+		{"account ID", 1, &result.AccountId},
+		{"application ID", 2, &result.AppId},
+		{"build ID", 3, &result.BuildId},
+		{"analysis ID", 4, &result.AnalysisId},
+		{"analysis unit ID", 5, &result.UnitId},
+		{"sandbox ID", 9, &result.SandboxId},
 	}
 
-	var urlFragment = strings.Split(urlOrAccountId, "#")[1]
+	for _, field := range fields {
+		if field.index >= len(parts) || len(strings.TrimSpace(parts[field.index])) == 0 {
+			continue
+		}
 
-	if isParseableURL(urlFragment) {
-		accountId, err := strconv.Atoi(strings.Split(urlFragment, ":")[1])
+		value, err := strconv.Atoi(strings.TrimSpace(parts[field.index]))
 
-		if err != nil {
-			platformUrlInvalid(urlOrAccountId)
+		if err != nil || value < 0 {
+			return PlatformUrl{}, fmt.Errorf("the %s \"%s\" is not a number", field.name, parts[field.index])
 		}
 
-		return accountId
-
+		*field.target = value
 	}
 
-	platformUrlInvalid(urlOrAccountId)
-	return -1
-}
-
-func parseAppIdFromPlatformUrl(urlOrAppId string) int {
-	appId, err := strconv.Atoi(urlOrAppId)
+	if result.AccountId == 0 {
+		return PlatformUrl{}, fmt.Errorf("the URL does not contain an account ID")
+	}
 
-	if err == nil {
-		return appId
+	if result.AppId == 0 {
+		return PlatformUrl{}, fmt.Errorf("the URL does not contain an application ID")
 	}
 
-	if !isPlatformURL(urlOrAppId) {
-		platformUrlInvalid(urlOrAppId)
+	if hasBuildContext && result.BuildId == 0 {
+		return PlatformUrl{}, fmt.Errorf("the URL does not contain a build ID")
 	}
 
-	var urlFragment = strings.Split(urlOrAppId, "#")[1]
+	return result, nil
+}
 
-	if isParseableURL(urlFragment) {
-		appId, err := strconv.Atoi(strings.Split(urlFragment, ":")[2])
+func parseRegionFromUrl(platformUrl string) string {
+	parsedUrl, err := url.Parse(strings.TrimSpace(platformUrl))
 
-		if err != nil {
-			platformUrlInvalid(urlOrAppId)
+	if err == nil {
+		if region, isPlatformHost := platformHosts[strings.ToLower(parsedUrl.Host)]; isPlatformHost {
+			return region
 		}
-
-		return appId
-
 	}
 
-	platformUrlInvalid(urlOrAppId)
-	return -1
+	return "commercial"
 }
 
-func parseBuildIdFromPlatformUrl(urlOrBuildId string) int {
-	buildId, err := strconv.Atoi(urlOrBuildId)
+func parseBaseUrlFromRegion(region string) string {
+	if region == "us" {
+		return "https://analysiscenter.veracode.us"
+	}
 
-	if err == nil {
-		return buildId
+	if region == "european" {
+		return "https://analysiscenter.veracode.eu"
 	}
 
-	if !isPlatformURL(urlOrBuildId) {
-		platformUrlInvalid(urlOrBuildId)
+	return "https://analysiscenter.veracode.com"
+}
+
+func parseAccountIdFromPlatformUrl(urlOrAccountId string) (int, error) {
+	if accountId, err := strconv.Atoi(strings.TrimSpace(urlOrAccountId)); err == nil {
+		return accountId, nil
 	}
 
-	var urlFragment = strings.Split(urlOrBuildId, "#")[1]
+	platformUrl, err := parsePlatformUrl(urlOrAccountId)
+	return platformUrl.AccountId, err
+}
 
-	if isParseableURL(urlFragment) {
-		buildId, err := strconv.Atoi(strings.Split(urlFragment, ":")[3])
+func parseAppIdFromPlatformUrl(urlOrAppId string) (int, error) {
+	if appId, err := strconv.Atoi(strings.TrimSpace(urlOrAppId)); err == nil {
+		return appId, nil
+	}
 
-		if err != nil {
-			platformUrlInvalid(urlOrBuildId)
-		}
+	platformUrl, err := parsePlatformUrl(urlOrAppId)
+	return platformUrl.AppId, err
+}
+
+func parseBuildIdFromPlatformUrl(urlOrBuildId string) (int, error) {
+	if buildId, err := strconv.Atoi(strings.TrimSpace(urlOrBuildId)); err == nil {
+		return buildId, nil
+	}
 
-		return buildId
+	platformUrl, err := parsePlatformUrl(urlOrBuildId)
 
+	if err == nil && platformUrl.BuildId == 0 {
+		return 0, fmt.Errorf("the page \"%s\" does not refer to a scan", platformUrl.Page)
 	}
 
-	platformUrlInvalid(urlOrBuildId)
-	return -1
+	return platformUrl.BuildId, err
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParsePlatformUrlSupportedPages(t *testing.T) {
+	for _, page := range supportedPages {
+		t.Run(page, func(t *testing.T) {
+			result, err := parsePlatformUrl(fmt.Sprintf("https://analysiscenter.veracode.com/auth/index.jsp#%s:1:2:3:4:5::::6", page))
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := PlatformUrl{Region: "commercial", Page: page, AccountId: 1, AppId: 2, BuildId: 3, AnalysisId: 4, UnitId: 5, SandboxId: 6}
+
+			if result != expected {
+				t.Errorf("got %+v, expected %+v", result, expected)
+			}
+		})
+	}
+
+	for _, page := range supportedApplicationPages {
+		t.Run(page, func(t *testing.T) {
+			result, err := parsePlatformUrl(fmt.Sprintf("https://analysiscenter.veracode.com/auth/index.jsp#%s:1:2", page))
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := PlatformUrl{Region: "commercial", Page: page, AccountId: 1, AppId: 2}
+
+			if result != expected {
+				t.Errorf("got %+v, expected %+v", result, expected)
+			}
+		})
+	}
+}
+
+func TestParsePlatformUrlRegions(t *testing.T) {
+	for host, region := range platformHosts {
+		t.Run(host, func(t *testing.T) {
+			result, err := parsePlatformUrl(fmt.Sprintf("https://%s/auth/index.jsp#StaticOverview:1:2:3", host))
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Region != region {
+				t.Errorf("got region %s, expected %s", result.Region, region)
+			}
+		})
+	}
+}
+
+func TestParsePlatformUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected PlatformUrl
+		isError  bool
+	}{
+		{"missing fragment", "https://analysiscenter.veracode.com/auth/index.jsp", PlatformUrl{}, true},
+		{"empty fragment", "https://analysiscenter.veracode.com/auth/index.jsp#", PlatformUrl{}, true},
+		{"trailing query parameters", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3?foo=bar", PlatformUrl{Region: "commercial", Page: "StaticOverview", AccountId: 1, AppId: 2, BuildId: 3}, false},
+		{"extra parameters", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3&foo=bar&baz=1", PlatformUrl{Region: "commercial", Page: "StaticOverview", AccountId: 1, AppId: 2, BuildId: 3}, false},
+		{"extra fragment fields", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3:4:5::::6:7:8", PlatformUrl{Region: "commercial", Page: "StaticOverview", AccountId: 1, AppId: 2, BuildId: 3, AnalysisId: 4, UnitId: 5, SandboxId: 6}, false},
+		{"url encoded fragment", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview%3A1%3A2%3A3", PlatformUrl{Region: "commercial", Page: "StaticOverview", AccountId: 1, AppId: 2, BuildId: 3}, false},
+		{"surrounding whitespace", "  https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3  ", PlatformUrl{Region: "commercial", Page: "StaticOverview", AccountId: 1, AppId: 2, BuildId: 3}, false},
+		{"unsupported page", "https://analysiscenter.veracode.com/auth/index.jsp#Unknown:1:2:3", PlatformUrl{}, true},
+		{"not https", "http://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3", PlatformUrl{}, true},
+		{"not a platform host", "https://example.com/auth/index.jsp#StaticOverview:1:2:3", PlatformUrl{}, true},
+		{"not signed in", "https://analysiscenter.veracode.com/login/#StaticOverview:1:2:3", PlatformUrl{}, true},
+		{"non-numeric account ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:a:2:3", PlatformUrl{}, true},
+		{"non-numeric application ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:b:3", PlatformUrl{}, true},
+		{"non-numeric build ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:c", PlatformUrl{}, true},
+		{"non-numeric sandbox ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3:4:5::::d", PlatformUrl{}, true},
+		{"negative build ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:-3", PlatformUrl{}, true},
+		{"zero account ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:0:2:3", PlatformUrl{}, true},
+		{"zero application ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:0:3", PlatformUrl{}, true},
+		{"zero build ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:0", PlatformUrl{}, true},
+		{"missing build ID", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2", PlatformUrl{}, true},
+		{"application page without build ID", "https://analysiscenter.veracode.com/auth/index.jsp#HomeAppProfile:1:2", PlatformUrl{Region: "commercial", Page: "HomeAppProfile", AccountId: 1, AppId: 2}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := parsePlatformUrl(test.url)
+
+			if test.isError {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", result)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result != test.expected {
+				t.Errorf("got %+v, expected %+v", result, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseBuildIdFromPlatformUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		isError  bool
+	}{
+		{"build ID", "123", 123, false},
+		{"build ID with whitespace", " 123 ", 123, false},
+		{"platform URL", "https://analysiscenter.veracode.com/auth/index.jsp#StaticOverview:1:2:3", 3, false},
+		{"application page", "https://analysiscenter.veracode.com/auth/index.jsp#HomeAppProfile:1:2", 0, true},
+		{"not a number or URL", "abc", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := parseBuildIdFromPlatformUrl(test.input)
+
+			if (err != nil) != test.isError {
+				t.Fatalf("got error %v, expected error = %v", err, test.isError)
+			}
+
+			if result != test.expected {
+				t.Errorf("got %d, expected %d", result, test.expected)
+			}
+		})
+	}
+}
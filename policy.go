@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+func (flaw DetailedReportFlaw) isAffectingCompliance() bool {
+	return flaw.isFlawOpen() && flaw.AffectsPolicyCompliance
+}
+
+func (report DetailedReport) getFormattedPolicy() string {
+	return fmt.Sprintf("\"%s\" version %d", report.PolicyName, report.PolicyVersion)
+}
+
+func getFormattedComplianceStatus(status string) string {
+	switch status {
+	case "Pass":
+		return color.HiGreenString(status)
+	case "Conditional Pass":
+		return color.HiYellowString(status)
+	case "Did Not Pass":
+		return color.HiRedString(status)
+	}
+
+	return status
+}
+
+func getFormattedPolicyValues(label, scanAValue, scanBValue string) string {
+	if scanAValue == scanBValue {
+		return fmt.Sprintf("%-20s%s\n", label+":", scanAValue)
+	}
+
+	return fmt.Sprintf("%-20s%s: %s, %s: %s\n", label+":", getFormattedSideString("A"), scanAValue, getFormattedSideString("B"), scanBValue)
+}
+
+func (data Data) reportPolicyDifferences() {
+	var report strings.Builder
+
+	scanAReport, scanBReport := data.ScanAReport, data.ScanBReport
+	flips := data.getComplianceFlips()
+
+	// Scans with no policy evaluated have nothing to compare
+	if len(scanAReport.PolicyName) == 0 && len(scanBReport.PolicyName) == 0 && len(flips) == 0 {
+		return
+	}
+
+	report.WriteString(getFormattedPolicyValues("Policy", scanAReport.getFormattedPolicy(), scanBReport.getFormattedPolicy()))
+	report.WriteString(getFormattedPolicyValues("Compliance status", getFormattedComplianceStatus(scanAReport.PolicyComplianceStatus), getFormattedComplianceStatus(scanBReport.PolicyComplianceStatus)))
+	report.WriteString(getFormattedPolicyValues("Rules status", getFormattedComplianceStatus(scanAReport.PolicyRulesStatus), getFormattedComplianceStatus(scanBReport.PolicyRulesStatus)))
+
+	if scanAReport.StaticAnalysis.Score == scanBReport.StaticAnalysis.Score {
+		report.WriteString(fmt.Sprintf("%-20s%d\n", "Score:", scanAReport.StaticAnalysis.Score))
+	} else {
+		report.WriteString(fmt.Sprintf("%-20s%s: %d, %s: %d (%+d)\n",
+			"Score:",
+			getFormattedSideString("A"),
+			scanAReport.StaticAnalysis.Score,
+			getFormattedSideString("B"),
+			scanBReport.StaticAnalysis.Score,
+			scanBReport.StaticAnalysis.Score-scanAReport.StaticAnalysis.Score))
+	}
+
+	if scanAReport.PolicyName == scanBReport.PolicyName && scanAReport.PolicyVersion != scanBReport.PolicyVersion {
+		report.WriteString(color.HiYellowString("\nThe policy was updated between these scans. This can change which flaws affect policy compliance\n"))
+	} else if scanAReport.PolicyName != scanBReport.PolicyName {
+		report.WriteString(color.HiYellowString("\nThese scans were evaluated against different policies. This can change which flaws affect policy compliance\n"))
+	}
+
+	if len(flips) > 0 {
+		report.WriteString("\nFlaw differences which changed policy compliance:\n")
+		report.WriteString(flips)
+	}
+
+	data.printTitle("Policy")
+	data.colorPrintf(report.String())
+}
+
+// Returns the flaws which affect policy compliance in one scan but not the other, and why
+func (data Data) getComplianceFlips() string {
+	var report strings.Builder
+
+	for _, scanAFlaw := range getFlawsSortedBySeverity(data.ScanAReport.Flaws) {
+		scanBFlaw := data.ScanBReport.getMatchingFlaw(scanAFlaw)
+
+		if scanBFlaw.ID == 0 {
+			if scanAFlaw.isAffectingCompliance() {
+				report.WriteString(fmt.Sprintf("%s %d (%s): No longer affects compliance (not reported in %s)\n", getFormattedSeverity(scanAFlaw.Severity), scanAFlaw.ID, getFormattedCwe(scanAFlaw.CWE), getFormattedSideString("B")))
+			}
+
+			continue
+		}
+
+		if scanAFlaw.isAffectingCompliance() == scanBFlaw.isAffectingCompliance() {
+			continue
+		}
+
+		var reason string
+
+		switch {
+		case scanAFlaw.AffectsPolicyCompliance != scanBFlaw.AffectsPolicyCompliance:
+			reason = "the policy rules changed"
+		case scanAFlaw.RemediationStatus != scanBFlaw.RemediationStatus:
+			reason = fmt.Sprintf("remediation status %s => %s", scanAFlaw.RemediationStatus, scanBFlaw.RemediationStatus)
+		default:
+			reason = fmt.Sprintf("mitigation status %s => %s", scanAFlaw.MitigationStatus, scanBFlaw.MitigationStatus)
+		}
+
+		var change = "No longer affects compliance"
+
+		if scanBFlaw.isAffectingCompliance() {
+			change = color.HiRedString("Now affects compliance")
+		}
+
+		report.WriteString(fmt.Sprintf("%s %d (%s): %s (%s)\n", getFormattedSeverity(scanAFlaw.Severity), scanAFlaw.ID, getFormattedCwe(scanAFlaw.CWE), change, reason))
+	}
+
+	for _, scanBFlaw := range getFlawsSortedBySeverity(data.ScanBReport.Flaws) {
+		if scanBFlaw.isAffectingCompliance() && !data.ScanAReport.isFlawInReport(scanBFlaw.MatchId) {
+			report.WriteString(fmt.Sprintf("%s %d (%s): %s (not reported in %s)\n", getFormattedSeverity(scanBFlaw.Severity), scanBFlaw.ID, getFormattedCwe(scanBFlaw.CWE), color.HiRedString("Now affects compliance"), getFormattedSideString("A")))
+		}
+	}
+
+	return report.String()
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"os"
 	"sort"
 	"strings"
+
+	"github.com/fatih/color"
 )
 
 type PrescanModuleList struct {
@@ -32,8 +35,24 @@ type PrescanModuleIssue struct {
 }
 
 func (api API) getPrescanModuleList(appId, buildId int) PrescanModuleList {
+	moduleList, err := api.tryGetPrescanModuleList(appId, buildId)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
+		os.Exit(1)
+	}
+
+	return moduleList
+}
+
+// An empty list is returned if the pre-scan results are not available
+func (api API) tryGetPrescanModuleList(appId, buildId int) (PrescanModuleList, error) {
 	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/getprescanresults.do?app_id=%d&build_id=%d", appId, buildId)
-	response := api.makeApiRequest(url, http.MethodGet)
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return PrescanModuleList{}, err
+	}
 
 	moduleList := PrescanModuleList{}
 	xml.Unmarshal(response, &moduleList)
@@ -43,7 +62,7 @@ func (api API) getPrescanModuleList(appId, buildId int) PrescanModuleList {
 		return moduleList.Modules[i].Name < moduleList.Modules[j].Name
 	})
 
-	return moduleList
+	return moduleList, nil
 }
 
 func (moduleList PrescanModuleList) getFromName(moduleName string) PrescanModule {
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// The risk of an open flaw is the product of these weights, so a severity-5 command injection
+// which affects policy counts for far more than a severity-1 information leak
+type RiskModel struct {
+	SeverityWeights       map[int]float64    `json:"severity_weights"`
+	CweWeights            map[int]float64    `json:"cwe_weights"` // Overrides the CWE Top 25 weight
+	CweTop25Weight        float64            `json:"cwe_top_25_weight"`
+	PolicyAffectingWeight float64            `json:"policy_affecting_weight"`
+	ExploitabilityWeights map[string]float64 `json:"exploitability_weights"` // Keyed by exploit level, -2 (very unlikely) to 2 (very likely)
+}
+
+func getDefaultRiskModel() RiskModel {
+	return RiskModel{
+		SeverityWeights:       map[int]float64{0: 0, 1: 1, 2: 2, 3: 5, 4: 10, 5: 20},
+		CweWeights:            map[int]float64{},
+		CweTop25Weight:        1.5,
+		PolicyAffectingWeight: 2,
+		ExploitabilityWeights: map[string]float64{"-2": 0.5, "-1": 0.75, "0": 1, "1": 1.5, "2": 2},
+	}
+}
+
+// Any weights not in the file keep their default values
+func parseRiskModel(riskModelFile string) RiskModel {
+	model := getDefaultRiskModel()
+
+	if len(riskModelFile) == 0 {
+		return model
+	}
+
+	content, err := os.ReadFile(riskModelFile)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not read the risk model file \"%s\"", riskModelFile))
+		os.Exit(1)
+	}
+
+	if err := json.Unmarshal(content, &model); err != nil {
+		color.HiRed(fmt.Sprintf("Error: Could not parse the risk model file \"%s\": %v", riskModelFile, err))
+		os.Exit(1)
+	}
+
+	var weights = []float64{model.CweTop25Weight, model.PolicyAffectingWeight}
+
+	for _, weight := range model.SeverityWeights {
+		weights = append(weights, weight)
+	}
+
+	for _, weight := range model.CweWeights {
+		weights = append(weights, weight)
+	}
+
+	for _, weight := range model.ExploitabilityWeights {
+		weights = append(weights, weight)
+	}
+
+	for _, weight := range weights {
+		if weight < 0 {
+			color.HiRed(fmt.Sprintf("Error: The risk model file \"%s\" contains a negative weight", riskModelFile))
+			os.Exit(1)
+		}
+	}
+
+	return model
+}
+
+func (model RiskModel) getCweWeight(cwe int) float64 {
+	if weight, found := model.CweWeights[cwe]; found {
+		return weight
+	}
+
+	if entry, found := cweCatalogue[cwe]; found && entry.Top25Rank > 0 {
+		return model.CweTop25Weight
+	}
+
+	return 1
+}
+
+// Closed and mitigated flaws carry no risk
+func (model RiskModel) getFlawRisk(flaw DetailedReportFlaw) float64 {
+	if !flaw.isFlawOpen() {
+		return 0
+	}
+
+	risk := model.SeverityWeights[flaw.Severity] * model.getCweWeight(flaw.CWE)
+
+	if flaw.AffectsPolicyCompliance {
+		risk *= model.PolicyAffectingWeight
+	}
+
+	if weight, found := model.ExploitabilityWeights[strings.TrimSpace(flaw.ExploitLevel)]; found {
+		risk *= weight
+	}
+
+	return risk
+}
+
+func (model RiskModel) getRiskScore(report DetailedReport) float64 {
+	var score = 0.0
+
+	for _, flaw := range report.Flaws {
+		score += model.getFlawRisk(flaw)
+	}
+
+	return score
+}
+
+// Returns the change in risk from A to B keyed by the value returned from getKey
+func getRiskDeltaBreakdown[K comparable](data Data, getKey func(flaw DetailedReportFlaw) K) map[K]float64 {
+	deltas := make(map[K]float64)
+
+	for _, flaw := range data.ScanAReport.Flaws {
+		deltas[getKey(flaw)] -= data.RiskModel.getFlawRisk(flaw)
+	}
+
+	for _, flaw := range data.ScanBReport.Flaws {
+		deltas[getKey(flaw)] += data.RiskModel.getFlawRisk(flaw)
+	}
+
+	for key, delta := range deltas {
+		if math.Abs(delta) < 0.05 {
+			delete(deltas, key)
+		}
+	}
+
+	return deltas
+}
+
+func (data Data) getRiskDeltaByModule() map[string]float64 {
+	return getRiskDeltaBreakdown(data, func(flaw DetailedReportFlaw) string {
+		return flaw.Module
+	})
+}
+
+// Keyed by the CWE ID, which the text report formats with the CWE name
+func (data Data) getRiskDeltaByCwe() map[int]float64 {
+	return getRiskDeltaBreakdown(data, func(flaw DetailedReportFlaw) int {
+		return flaw.CWE
+	})
+}
+
+// Largest change first
+func getKeysSortedByAbsoluteDelta(deltas map[string]float64) []string {
+	var keys []string
+
+	for key := range deltas {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if math.Abs(deltas[keys[i]]) != math.Abs(deltas[keys[j]]) {
+			return math.Abs(deltas[keys[i]]) > math.Abs(deltas[keys[j]])
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+func getFormattedRiskDelta(delta float64) string {
+	var formatted = fmt.Sprintf("%+.1f", delta)
+
+	if delta >= 0.05 {
+		return color.HiRedString(formatted)
+	} else if delta <= -0.05 {
+		return color.HiGreenString(formatted)
+	}
+
+	return formatted
+}
+
+func (data Data) getRiskScoreSummary() string {
+	scanAScore := data.RiskModel.getRiskScore(data.ScanAReport)
+	scanBScore := data.RiskModel.getRiskScore(data.ScanBReport)
+
+	if scanAScore == 0 && scanBScore == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Risk score:         %s = %.1f, %s = %.1f (%s)\n",
+		getFormattedSideString("A"),
+		scanAScore,
+		getFormattedSideString("B"),
+		scanBScore,
+		getFormattedRiskDelta(scanBScore-scanAScore))
+}
+
+func (data Data) reportRiskDelta() {
+	var report strings.Builder
+
+	writeBreakdown := func(title string, deltas map[string]float64) {
+		if len(deltas) == 0 {
+			return
+		}
+
+		report.WriteString(fmt.Sprintf("%s:\n", title))
+
+		for _, key := range getKeysSortedByAbsoluteDelta(deltas) {
+			report.WriteString(fmt.Sprintf("  %s: %s\n", key, getFormattedRiskDelta(deltas[key])))
+		}
+	}
+
+	writeBreakdown("By module", data.getRiskDeltaByModule())
+
+	cweDeltas := make(map[string]float64)
+
+	for cwe, delta := range data.getRiskDeltaByCwe() {
+		cweDeltas[getFormattedCwe(cwe)] = delta
+	}
+
+	writeBreakdown("By CWE", cweDeltas)
+
+	if report.Len() > 0 {
+		data.printTitle("Risk Score Delta")
+		data.colorPrintf(report.String())
+	}
+}
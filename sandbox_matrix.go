@@ -0,0 +1,171 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type SandboxMatrixRow struct {
+	SandboxName          string
+	BuildId              int
+	NewOpenPolicyFlaws   int
+	ClosedFlaws          int
+	SelectedModuleDrift  int
+	EngineVersion        string
+	EngineVersionChanged bool
+
+	// Platform URLs for each cell
+	NewOpenPolicyFlawsUrl  string
+	ClosedFlawsUrl         string
+	SelectedModuleDriftUrl string
+	EngineVersionUrl       string
+}
+
+func runSandboxMatrix(vid, vkey, profile, region, app string, pathRules PathRules) {
+	if len(app) < 1 {
+		color.HiRed("Error: No Veracode Platform URL or application ID specified. Expected: \"scan_compare -action sandboxes -app https://analysiscenter.veracode.com/auth/index.jsp...\"")
+		print("\nUsage:\n")
+		flag.PrintDefaults()
+		return
+	}
+
+	regionToUse := getRegionToUse(region, app)
+	appId, err := parseAppIdFromPlatformUrl(app)
+
+	if err != nil {
+		platformUrlInvalid(app, err)
+	}
+
+	api := getApiForSide(vid, vkey, profile, "", regionToUse, parseAccountIdFromScanUrl(app))
+
+	policyBuildId := api.getLatestBuildIdWithResults(appId, 0)
+
+	if policyBuildId == 0 {
+		color.HiRed(fmt.Sprintf("Error: Could not find a policy scan with results for application id %d", appId))
+		os.Exit(1)
+	}
+
+	sandboxes := api.getSandboxList(appId).Sandboxes
+
+	if len(sandboxes) == 0 {
+		color.HiRed(fmt.Sprintf("Error: There are no sandboxes for application id %d", appId))
+		os.Exit(1)
+	}
+
+	colorPrintf(fmt.Sprintf("Comparing the latest scan of %d sandboxes against the %s in the %s region\n",
+		len(sandboxes),
+		color.HiGreenString("latest policy scan (Build id = %d)", policyBuildId),
+		api.region))
+
+	var rows []SandboxMatrixRow
+	var sandboxesWithoutResults []string
+
+	for _, sandbox := range sandboxes {
+		sandboxBuildId := api.getLatestBuildIdWithResults(appId, sandbox.ID)
+
+		if sandboxBuildId == 0 {
+			sandboxesWithoutResults = append(sandboxesWithoutResults, sandbox.Name)
+			continue
+		}
+
+		data := getData(api, api, policyBuildId, sandboxBuildId, "id", pathRules)
+		rows = append(rows, data.getSandboxMatrixRow(sandbox.Name, sandboxBuildId))
+	}
+
+	reportSandboxMatrix(rows)
+
+	if len(sandboxesWithoutResults) > 0 {
+		printTitle("Sandboxes Without Scan Results")
+		color.HiYellow(strings.Join(sandboxesWithoutResults, "\n"))
+	}
+}
+
+// Scan A is the policy scan and scan B is the sandbox scan
+func (data Data) getSandboxMatrixRow(sandboxName string, sandboxBuildId int) SandboxMatrixRow {
+	return SandboxMatrixRow{
+		SandboxName:          sandboxName,
+		BuildId:              sandboxBuildId,
+		NewOpenPolicyFlaws:   len(getFlawsOnlyInThisScan(data.ScanBReport, data.ScanAReport, true, false)),
+		ClosedFlaws:          len(getFlawsClosedInOtherScan(data.ScanAReport, data.ScanBReport)),
+		SelectedModuleDrift:  getSelectedModuleDriftCount(data.ScanAReport.StaticAnalysis.Modules, data.ScanBReport.StaticAnalysis.Modules),
+		EngineVersion:        data.ScanBReport.StaticAnalysis.EngineVersion,
+		EngineVersionChanged: data.ScanAReport.StaticAnalysis.EngineVersion != data.ScanBReport.StaticAnalysis.EngineVersion,
+
+		// New flaws are triaged in the sandbox, closed flaws are still open in the policy scan
+		NewOpenPolicyFlawsUrl:  data.ScanBReport.getTriageFlawsUrl(data.ScanBRegion),
+		ClosedFlawsUrl:         data.ScanAReport.getTriageFlawsUrl(data.ScanARegion),
+		SelectedModuleDriftUrl: data.ScanBReport.getReviewModulesUrl(data.ScanBRegion),
+		EngineVersionUrl:       data.ScanBReport.getPlatformUrl(data.ScanBRegion, "StaticOverview"),
+	}
+}
+
+func getSandboxMatrixCell(value int, width int, colorIfNonZero func(format string, a ...interface{}) string) string {
+	cell := fmt.Sprintf("%-*d", width, value)
+
+	if value > 0 {
+		return colorIfNonZero(cell)
+	}
+
+	return cell
+}
+
+func reportSandboxMatrix(rows []SandboxMatrixRow) {
+	if len(rows) == 0 {
+		return
+	}
+
+	var sandboxColumnWidth = len("Sandbox")
+
+	for _, row := range rows {
+		if len(row.SandboxName) > sandboxColumnWidth {
+			sandboxColumnWidth = len(row.SandboxName)
+		}
+	}
+
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("%-*s  %-15s  %-12s  %-12s  %s\n",
+		sandboxColumnWidth,
+		"Sandbox",
+		"New Open Policy",
+		"Closed Flaws",
+		"Module Drift",
+		"Engine Version"))
+
+	for _, row := range rows {
+		engineVersion := row.EngineVersion
+
+		if row.EngineVersionChanged {
+			engineVersion = color.HiYellowString(engineVersion)
+		}
+
+		report.WriteString(fmt.Sprintf("%-*s  %s  %s  %s  %s\n",
+			sandboxColumnWidth,
+			row.SandboxName,
+			getSandboxMatrixCell(row.NewOpenPolicyFlaws, 15, color.HiRedString),
+			getSandboxMatrixCell(row.ClosedFlaws, 12, color.HiGreenString),
+			getSandboxMatrixCell(row.SelectedModuleDrift, 12, color.HiYellowString),
+			engineVersion))
+	}
+
+	// The URLs are too long to show within the table
+	var links strings.Builder
+
+	for _, row := range rows {
+		links.WriteString(fmt.Sprintf("%s:\n", color.HiCyanString(row.SandboxName)))
+		links.WriteString(fmt.Sprintf("  %-16s %s\n", "New Open Policy:", row.NewOpenPolicyFlawsUrl))
+		links.WriteString(fmt.Sprintf("  %-16s %s\n", "Closed Flaws:", row.ClosedFlawsUrl))
+		links.WriteString(fmt.Sprintf("  %-16s %s\n", "Module Drift:", row.SelectedModuleDriftUrl))
+		links.WriteString(fmt.Sprintf("  %-16s %s\n", "Engine Version:", row.EngineVersionUrl))
+	}
+
+	printTitle("Sandbox Matrix (Compared Against The Latest Policy Scan)")
+	colorPrintf(report.String())
+
+	printTitle("Sandbox Matrix Links")
+	colorPrintf(links.String())
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/fatih/color"
+)
+
+type SandboxList struct {
+	XMLName   xml.Name  `xml:"sandboxlist"`
+	Sandboxes []Sandbox `xml:"sandbox"`
+}
+
+type Sandbox struct {
+	XMLName xml.Name `xml:"sandbox"`
+	ID      int      `xml:"sandbox_id,attr"`
+	Name    string   `xml:"sandbox_name,attr"`
+}
+
+func (api API) getSandboxList(appId int) SandboxList {
+	sandboxList, err := api.tryGetSandboxList(appId)
+
+	if err != nil {
+		color.HiRed(fmt.Sprintf("Error: %s", err))
+		os.Exit(1)
+	}
+
+	return sandboxList
+}
+
+func (api API) tryGetSandboxList(appId int) (SandboxList, error) {
+	var url = fmt.Sprintf("https://analysiscenter.veracode.com/api/5.0/getsandboxlist.do?app_id=%d", appId)
+	response, err := api.tryApiRequest(url, http.MethodGet)
+
+	if err != nil {
+		return SandboxList{}, err
+	}
+
+	sandboxList := SandboxList{}
+	xml.Unmarshal(response, &sandboxList)
+
+	// Sort sandboxes by name for consistency
+	sort.Slice(sandboxList.Sandboxes, func(i, j int) bool {
+		return sandboxList.Sandboxes[i].Name < sandboxList.Sandboxes[j].Name
+	})
+
+	return sandboxList, nil
+}
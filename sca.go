@@ -0,0 +1,351 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type DetailedReportSCA struct {
+	XMLName              xml.Name                  `xml:"software_composition_analysis"`
+	ThirdPartyComponents int                       `xml:"third_party_components,attr"`
+	ViolatePolicy        bool                      `xml:"violate_policy,attr"`
+	Components           []DetailedReportComponent `xml:"vulnerable_components>component"`
+}
+
+type DetailedReportComponent struct {
+	XMLName                 xml.Name                      `xml:"component"`
+	ID                      string                        `xml:"component_id,attr"`
+	FileName                string                        `xml:"file_name,attr"`
+	Library                 string                        `xml:"library,attr"`
+	Vendor                  string                        `xml:"vendor,attr"`
+	Version                 string                        `xml:"version,attr"`
+	MaxCVSSScore            float64                       `xml:"max_cvss_score,attr"`
+	AffectsPolicyCompliance bool                          `xml:"component_affects_policy_compliance,attr"`
+	Licenses                []DetailedReportLicense       `xml:"licenses>license"`
+	Vulnerabilities         []DetailedReportVulnerability `xml:"vulnerabilities>vulnerability"`
+}
+
+type DetailedReportLicense struct {
+	XMLName    xml.Name `xml:"license"`
+	Name       string   `xml:"name,attr"`
+	SpdxId     string   `xml:"spdx_id,attr"`
+	RiskRating int      `xml:"risk_rating,attr"`
+}
+
+type DetailedReportVulnerability struct {
+	XMLName                 xml.Name `xml:"vulnerability"`
+	CveId                   string   `xml:"cve_id,attr"`
+	CVSSScore               float64  `xml:"cvss_score,attr"`
+	Severity                int      `xml:"severity,attr"`
+	CweId                   string   `xml:"cwe_id,attr"`
+	AffectsPolicyCompliance bool     `xml:"vulnerability_affects_policy_compliance,attr"`
+}
+
+// Components are identified by vendor and library so version changes can be detected
+func (component DetailedReportComponent) getName() string {
+	if len(component.Library) == 0 {
+		return component.FileName
+	}
+
+	if len(component.Vendor) == 0 {
+		return component.Library
+	}
+
+	return fmt.Sprintf("%s:%s", component.Vendor, component.Library)
+}
+
+func getMaxLicenseRiskRating(components []DetailedReportComponent) int {
+	var maxRiskRating = 0
+
+	for _, component := range components {
+		for _, license := range component.Licenses {
+			if license.RiskRating > maxRiskRating {
+				maxRiskRating = license.RiskRating
+			}
+		}
+	}
+
+	return maxRiskRating
+}
+
+func getFormattedLicenses(components []DetailedReportComponent) string {
+	var licenses []string
+
+	for _, component := range components {
+		for _, license := range component.Licenses {
+			name := license.SpdxId
+
+			if len(name) == 0 {
+				name = license.Name
+			}
+
+			if !isStringInStringArray(name, licenses) {
+				licenses = append(licenses, name)
+			}
+		}
+	}
+
+	if len(licenses) == 0 {
+		return "no licence"
+	}
+
+	return strings.Join(licenses, ", ")
+}
+
+func getComponentsMatchingVersions(components []DetailedReportComponent, versions []string, matching bool) []DetailedReportComponent {
+	var matchingComponents []DetailedReportComponent
+
+	for _, component := range components {
+		if isStringInStringArray(component.Version, versions) == matching {
+			matchingComponents = append(matchingComponents, component)
+		}
+	}
+
+	return matchingComponents
+}
+
+func getComponentsByName(components []DetailedReportComponent) map[string][]DetailedReportComponent {
+	componentsByName := make(map[string][]DetailedReportComponent)
+
+	for _, component := range components {
+		componentsByName[component.getName()] = append(componentsByName[component.getName()], component)
+	}
+
+	return componentsByName
+}
+
+func getSortedComponentNames(componentsByName map[string][]DetailedReportComponent) []string {
+	names := make([]string, 0, len(componentsByName))
+
+	for name := range componentsByName {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func getComponentVersions(components []DetailedReportComponent) []string {
+	var versions []string
+
+	for _, component := range components {
+		if !isStringInStringArray(component.Version, versions) {
+			versions = append(versions, component.Version)
+		}
+	}
+
+	sort.Strings(versions)
+
+	return versions
+}
+
+type componentVulnerability struct {
+	component     string
+	vulnerability DetailedReportVulnerability
+}
+
+// Returns the CVEs keyed by component name and CVE ID, so the same CVE in different components is counted separately
+func getComponentVulnerabilities(components []DetailedReportComponent) map[string]componentVulnerability {
+	vulnerabilities := make(map[string]componentVulnerability)
+
+	for _, component := range components {
+		for _, vulnerability := range component.Vulnerabilities {
+			vulnerabilities[component.getName()+"|"+vulnerability.CveId] = componentVulnerability{component.getName(), vulnerability}
+		}
+	}
+
+	return vulnerabilities
+}
+
+// Returns the vulnerabilities only in this scan, highest CVSS score first
+func getComponentVulnerabilitiesOnlyInThisScan(thisSideComponents, otherSideComponents []DetailedReportComponent) []componentVulnerability {
+	otherSideVulnerabilities := getComponentVulnerabilities(otherSideComponents)
+	var vulnerabilitiesOnlyInThisScan []componentVulnerability
+
+	for key, vulnerability := range getComponentVulnerabilities(thisSideComponents) {
+		if _, found := otherSideVulnerabilities[key]; !found {
+			vulnerabilitiesOnlyInThisScan = append(vulnerabilitiesOnlyInThisScan, vulnerability)
+		}
+	}
+
+	sort.Slice(vulnerabilitiesOnlyInThisScan, func(i, j int) bool {
+		if vulnerabilitiesOnlyInThisScan[i].vulnerability.CVSSScore != vulnerabilitiesOnlyInThisScan[j].vulnerability.CVSSScore {
+			return vulnerabilitiesOnlyInThisScan[i].vulnerability.CVSSScore > vulnerabilitiesOnlyInThisScan[j].vulnerability.CVSSScore
+		}
+
+		if vulnerabilitiesOnlyInThisScan[i].vulnerability.CveId != vulnerabilitiesOnlyInThisScan[j].vulnerability.CveId {
+			return vulnerabilitiesOnlyInThisScan[i].vulnerability.CveId < vulnerabilitiesOnlyInThisScan[j].vulnerability.CveId
+		}
+
+		return vulnerabilitiesOnlyInThisScan[i].component < vulnerabilitiesOnlyInThisScan[j].component
+	})
+
+	return vulnerabilitiesOnlyInThisScan
+}
+
+func isAnyComponentViolatingPolicy(components []DetailedReportComponent) bool {
+	for _, component := range components {
+		if component.AffectsPolicyCompliance {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (data Data) reportSCADifferences() {
+	scanAComponents := data.ScanAReport.SCA.Components
+	scanBComponents := data.ScanBReport.SCA.Components
+
+	if len(scanAComponents) == 0 && len(scanBComponents) == 0 {
+		return
+	}
+
+	data.reportComponentDifferences()
+	data.reportComponentVulnerabilityDifferences(scanAComponents, scanBComponents)
+	data.reportComponentPolicyDifferences()
+}
+
+func (data Data) reportComponentDifferences() {
+	var report strings.Builder
+
+	scanAComponentsByName := getComponentsByName(data.ScanAReport.SCA.Components)
+	scanBComponentsByName := getComponentsByName(data.ScanBReport.SCA.Components)
+
+	for _, side := range []string{"A", "B"} {
+		thisSideComponentsByName, otherSideComponentsByName := scanAComponentsByName, scanBComponentsByName
+
+		if side == "B" {
+			thisSideComponentsByName, otherSideComponentsByName = scanBComponentsByName, scanAComponentsByName
+		}
+
+		for _, name := range getSortedComponentNames(thisSideComponentsByName) {
+			if _, found := otherSideComponentsByName[name]; !found {
+				report.WriteString(fmt.Sprintf("%s: \"%s\" version %s\n", getFormattedOnlyInSideString(side), name, strings.Join(getComponentVersions(thisSideComponentsByName[name]), ", ")))
+			}
+		}
+	}
+
+	for _, name := range getSortedComponentNames(scanAComponentsByName) {
+		scanBComponents, found := scanBComponentsByName[name]
+
+		if !found {
+			continue
+		}
+
+		scanAComponents := scanAComponentsByName[name]
+		scanAVersions := getComponentVersions(scanAComponents)
+		scanBVersions := getComponentVersions(scanBComponents)
+
+		if strings.Join(scanAVersions, ", ") != strings.Join(scanBVersions, ", ") {
+			report.WriteString(fmt.Sprintf("\"%s\": version %s: %s => %s: %s\n", name, getFormattedSideString("A"), strings.Join(scanAVersions, ", "), getFormattedSideString("B"), strings.Join(scanBVersions, ", ")))
+		}
+
+		// Each version in both scans is compared, then the versions only in one scan are compared with each other, e.g. after an upgrade
+		for _, version := range scanAVersions {
+			if isStringInStringArray(version, scanBVersions) {
+				writeLicenceRiskDifference(&report, fmt.Sprintf("\"%s\" version %s", name, version), getComponentsMatchingVersions(scanAComponents, []string{version}, true), getComponentsMatchingVersions(scanBComponents, []string{version}, true))
+			}
+		}
+
+		scanAUpgradedComponents := getComponentsMatchingVersions(scanAComponents, scanBVersions, false)
+		scanBUpgradedComponents := getComponentsMatchingVersions(scanBComponents, scanAVersions, false)
+
+		if len(scanAUpgradedComponents) > 0 && len(scanBUpgradedComponents) > 0 {
+			writeLicenceRiskDifference(&report, fmt.Sprintf("\"%s\"", name), scanAUpgradedComponents, scanBUpgradedComponents)
+		}
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Third-Party Component Differences")
+		data.colorPrintf(report.String())
+	}
+}
+
+func writeLicenceRiskDifference(report *strings.Builder, description string, scanAComponents, scanBComponents []DetailedReportComponent) {
+	scanALicenseRisk := getMaxLicenseRiskRating(scanAComponents)
+	scanBLicenseRisk := getMaxLicenseRiskRating(scanBComponents)
+
+	if scanALicenseRisk == scanBLicenseRisk {
+		return
+	}
+
+	var licenceRiskChange = color.HiGreenString("licence risk decreased")
+
+	if scanBLicenseRisk > scanALicenseRisk {
+		licenceRiskChange = color.HiRedString("licence risk increased")
+	}
+
+	report.WriteString(fmt.Sprintf("%s: %s, %s: %s (risk %d) => %s: %s (risk %d)\n",
+		description,
+		licenceRiskChange,
+		getFormattedSideString("A"),
+		getFormattedLicenses(scanAComponents),
+		scanALicenseRisk,
+		getFormattedSideString("B"),
+		getFormattedLicenses(scanBComponents),
+		scanBLicenseRisk))
+}
+
+func (data Data) reportComponentVulnerabilityDifferences(scanAComponents, scanBComponents []DetailedReportComponent) {
+	var report strings.Builder
+
+	for _, side := range []string{"A", "B"} {
+		thisSideComponents, otherSideComponents := scanAComponents, scanBComponents
+
+		if side == "B" {
+			thisSideComponents, otherSideComponents = scanBComponents, scanAComponents
+		}
+
+		for _, vulnerability := range getComponentVulnerabilitiesOnlyInThisScan(thisSideComponents, otherSideComponents) {
+			var formattedPolicy = ""
+
+			if vulnerability.vulnerability.AffectsPolicyCompliance {
+				formattedPolicy = ", affects policy"
+			}
+
+			report.WriteString(fmt.Sprintf("%s: %s (CVSS %.1f%s) in \"%s\"\n",
+				getFormattedOnlyInSideString(side),
+				vulnerability.vulnerability.CveId,
+				vulnerability.vulnerability.CVSSScore,
+				formattedPolicy,
+				vulnerability.component))
+		}
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Third-Party Component Vulnerability Differences")
+		data.colorPrintf(report.String())
+	}
+}
+
+func (data Data) reportComponentPolicyDifferences() {
+	var report strings.Builder
+
+	scanAComponentsByName := getComponentsByName(data.ScanAReport.SCA.Components)
+	scanBComponentsByName := getComponentsByName(data.ScanBReport.SCA.Components)
+
+	for _, side := range []string{"A", "B"} {
+		thisSideComponentsByName, otherSideComponentsByName := scanAComponentsByName, scanBComponentsByName
+
+		if side == "B" {
+			thisSideComponentsByName, otherSideComponentsByName = scanBComponentsByName, scanAComponentsByName
+		}
+
+		for _, name := range getSortedComponentNames(thisSideComponentsByName) {
+			if isAnyComponentViolatingPolicy(thisSideComponentsByName[name]) && !isAnyComponentViolatingPolicy(otherSideComponentsByName[name]) {
+				report.WriteString(fmt.Sprintf("%s: \"%s\" version %s\n", getFormattedOnlyInSideString(side), name, strings.Join(getComponentVersions(thisSideComponentsByName[name]), ", ")))
+			}
+		}
+	}
+
+	if report.Len() > 0 {
+		data.printTitle("Policy Violating Third-Party Component Differences")
+		data.colorPrintf(report.String())
+	}
+}
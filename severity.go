@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var severityNames = []string{"Informational", "Very Low", "Low", "Medium", "High", "Very High"}
+
+func getSeverityName(severity int) string {
+	if severity < 0 || severity >= len(severityNames) {
+		return fmt.Sprintf("Unknown (%d)", severity)
+	}
+
+	return severityNames[severity]
+}
+
+func getFormattedSeverity(severity int) string {
+	var formatted = fmt.Sprintf("[%s]", getSeverityName(severity))
+
+	if severity >= 4 {
+		return color.HiRedString(formatted)
+	}
+
+	if severity == 3 {
+		return color.HiYellowString(formatted)
+	}
+
+	return formatted
+}
+
+// Highest known severity first. Severities outside the known range are listed afterwards rather than
+// being dropped, so the flaw counts still add up to the totals
+func getSeveritiesDescending(flaws []DetailedReportFlaw) []int {
+	var severities []int
+
+	for severity := len(severityNames) - 1; severity >= 0; severity-- {
+		severities = append(severities, severity)
+	}
+
+	var unknownSeverities []int
+
+	for _, flaw := range flaws {
+		if (flaw.Severity < 0 || flaw.Severity >= len(severityNames)) && !isInIntArray(flaw.Severity, unknownSeverities) {
+			unknownSeverities = append(unknownSeverities, flaw.Severity)
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(unknownSeverities)))
+
+	return append(severities, unknownSeverities...)
+}
+
+// Highest severity first, then by ID
+func getFlawsSortedBySeverity(flaws []DetailedReportFlaw) []DetailedReportFlaw {
+	sortedFlaws := make([]DetailedReportFlaw, len(flaws))
+	copy(sortedFlaws, flaws)
+
+	sort.SliceStable(sortedFlaws, func(i, j int) bool {
+		if sortedFlaws[i].Severity != sortedFlaws[j].Severity {
+			return sortedFlaws[i].Severity > sortedFlaws[j].Severity
+		}
+
+		return sortedFlaws[i].ID < sortedFlaws[j].ID
+	})
+
+	return sortedFlaws
+}
+
+func (report DetailedReport) getOpenFlawCountBySeverity(severity int) int {
+	var count = 0
+
+	for _, flaw := range report.Flaws {
+		if flaw.isFlawOpen() && flaw.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+func compareFlawSeverities(report *strings.Builder, thisSideReport, otherSideReport DetailedReport) {
+	for _, thisSideFlaw := range getFlawsSortedBySeverity(thisSideReport.Flaws) {
+		otherSideFlaw := otherSideReport.getMatchingFlaw(thisSideFlaw)
+
+		if otherSideFlaw.ID == 0 || thisSideFlaw.Severity == otherSideFlaw.Severity {
+			continue
+		}
+
+		var direction = color.HiGreenString("decreased")
+
+		if otherSideFlaw.Severity > thisSideFlaw.Severity {
+			direction = color.HiRedString("increased")
+		}
+
+		report.WriteString(fmt.Sprintf("%s %d (%s): %s: %s, %s: %s (%s)\n",
+			getFormattedSeverity(otherSideFlaw.Severity),
+			thisSideFlaw.ID,
+			getFormattedCwe(thisSideFlaw.CWE),
+			getFormattedSideString("A"),
+			getFormattedSeverity(thisSideFlaw.Severity),
+			getFormattedSideString("B"),
+			getFormattedSeverity(otherSideFlaw.Severity),
+			direction))
+	}
+}
+
+func (data Data) reportFlawSeverityDifferences() {
+	var report strings.Builder
+
+	compareFlawSeverities(&report, data.ScanAReport, data.ScanBReport)
+
+	if report.Len() > 0 {
+		data.printTitle("Flaw Severity Differences")
+		data.colorPrintf(report.String())
+	}
+}
+
+func (data Data) getOpenFlawSeverityDeltaSummary() string {
+	var summary strings.Builder
+
+	for _, severity := range getSeveritiesDescending(append(append([]DetailedReportFlaw{}, data.ScanAReport.Flaws...), data.ScanBReport.Flaws...)) {
+		scanACount := data.ScanAReport.getOpenFlawCountBySeverity(severity)
+		scanBCount := data.ScanBReport.getOpenFlawCountBySeverity(severity)
+
+		if scanACount == 0 && scanBCount == 0 {
+			continue
+		}
+
+		var delta = fmt.Sprintf("%+d", scanBCount-scanACount)
+
+		if scanBCount > scanACount {
+			delta = color.HiRedString(delta)
+		} else if scanBCount < scanACount {
+			delta = color.HiGreenString(delta)
+		}
+
+		summary.WriteString(fmt.Sprintf("Open %-14s %s = %d, %s = %d (%s)\n",
+			getSeverityName(severity)+":",
+			getFormattedSideString("A"),
+			scanACount,
+			getFormattedSideString("B"),
+			scanBCount,
+			delta))
+	}
+
+	return summary.String()
+}
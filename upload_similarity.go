@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+const (
+	UploadsIdentical          = "identical"
+	UploadsNearIdentical      = "near-identical"
+	UploadsPartiallyDifferent = "partially different"
+	UploadsFullyDifferent     = "fully different"
+)
+
+// The proportion of files which must match for the uploads to be considered near-identical
+const nearIdenticalUploadThreshold = 0.9
+
+type UploadSimilarity struct {
+	Classification string `json:"classification"`
+	MatchingFiles  int    `json:"matching_files"`
+	ScanAFiles     int    `json:"scan_a_files"`
+	ScanBFiles     int    `json:"scan_b_files"`
+}
+
+// Files match when both the name and MD5 are the same. Duplicate files are counted once per occurrence
+func (data Data) getUploadSimilarity() UploadSimilarity {
+	similarity := UploadSimilarity{
+		ScanAFiles: len(data.ScanAPrescanFileList.Files),
+		ScanBFiles: len(data.ScanBPrescanFileList.Files),
+	}
+
+	scanAFileCounts := make(map[string]int)
+
+	for _, file := range data.ScanAPrescanFileList.Files {
+		scanAFileCounts[file.Name+"|"+file.MD5]++
+	}
+
+	for _, file := range data.ScanBPrescanFileList.Files {
+		key := file.Name + "|" + file.MD5
+
+		if scanAFileCounts[key] > 0 {
+			scanAFileCounts[key]--
+			similarity.MatchingFiles++
+		}
+	}
+
+	mostFiles := similarity.ScanAFiles
+
+	if similarity.ScanBFiles > mostFiles {
+		mostFiles = similarity.ScanBFiles
+	}
+
+	switch {
+	case mostFiles > 0 && similarity.MatchingFiles == mostFiles:
+		similarity.Classification = UploadsIdentical
+	case mostFiles > 0 && float64(similarity.MatchingFiles)/float64(mostFiles) >= nearIdenticalUploadThreshold:
+		similarity.Classification = UploadsNearIdentical
+	case similarity.MatchingFiles == 0:
+		similarity.Classification = UploadsFullyDifferent
+	default:
+		similarity.Classification = UploadsPartiallyDifferent
+	}
+
+	return similarity
+}
+
+func (similarity UploadSimilarity) getFormattedSummary() string {
+	var formattedClassification = cases.Title(language.English).String(similarity.Classification)
+
+	switch similarity.Classification {
+	case UploadsIdentical:
+		formattedClassification = color.HiGreenString(formattedClassification)
+	case UploadsFullyDifferent:
+		formattedClassification = color.HiRedString(formattedClassification)
+	default:
+		formattedClassification = color.HiYellowString(formattedClassification)
+	}
+
+	return fmt.Sprintf("Uploads: %s (%d of %d files in %s and %d files in %s have the same name and MD5)\n",
+		formattedClassification,
+		similarity.MatchingFiles,
+		similarity.ScanAFiles,
+		getFormattedSideString("A"),
+		similarity.ScanBFiles,
+		getFormattedSideString("B"))
+}
+
+// Classifies a flaw only reported in one scan as code-driven, or engine/config-driven. When the uploads
+// are identical the code cannot be the cause, so anything not explained by configuration is down to the engine
+func (data Data) getFlawDifferenceDriver(side string, flaw DetailedReportFlaw, similarity UploadSimilarity) string {
+	driver, _ := data.explainFlawDifference(side, flaw)
+
+	if similarity.Classification == UploadsIdentical && (driver == FlawDifferenceDrivenByCode || driver == FlawDifferenceDrivenByUnknown) {
+		return FlawDifferenceDrivenByEngine
+	}
+
+	return driver
+}
+
+func (data Data) getFlawDifferenceDriverCounts() map[string]int {
+	similarity := data.getUploadSimilarity()
+	counts := make(map[string]int)
+
+	for _, side := range []string{"A", "B"} {
+		thisReport, otherReport := data.ScanAReport, data.ScanBReport
+
+		if side == "B" {
+			thisReport, otherReport = data.ScanBReport, data.ScanAReport
+		}
+
+		for _, flaw := range thisReport.Flaws {
+			if !otherReport.isFlawInReport(flaw.MatchId) {
+				counts[data.getFlawDifferenceDriver(side, flaw, similarity)]++
+			}
+		}
+	}
+
+	return counts
+}
+
+func (data Data) getUploadSimilaritySummary() string {
+	var report strings.Builder
+
+	report.WriteString(data.getUploadSimilarity().getFormattedSummary())
+
+	counts := data.getFlawDifferenceDriverCounts()
+
+	if len(counts) == 0 {
+		return report.String()
+	}
+
+	report.WriteString(fmt.Sprintf("Flaw differences: %d code-driven, %d engine/config-driven",
+		counts[FlawDifferenceDrivenByCode],
+		counts[FlawDifferenceDrivenByEngine]+counts[FlawDifferenceDrivenByConfiguration]))
+
+	if counts[FlawDifferenceDrivenByUnknown] > 0 {
+		report.WriteString(fmt.Sprintf(", %d unknown", counts[FlawDifferenceDrivenByUnknown]))
+	}
+
+	report.WriteString("\n")
+
+	return report.String()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"strconv"
@@ -15,6 +16,19 @@ func colorPrintf(format string) {
 	color.New().Printf(format)
 }
 
+// Reports are written to data.Output when set so several can be rendered at once without sharing os.Stdout
+func (data Data) getOutput() io.Writer {
+	if data.Output != nil {
+		return data.Output
+	}
+
+	return color.Output
+}
+
+func (data Data) colorPrintf(output string) {
+	fmt.Fprint(data.getOutput(), output)
+}
+
 func isStringInStringArray(input string, list []string) bool {
 	for _, item := range list {
 		if input == item {
@@ -108,6 +122,14 @@ func formatDuration(duration time.Duration) string {
 }
 
 func printTitle(title string) {
-	color.HiCyan("\n" + title)
-	fmt.Println(strings.Repeat("=", len(title)))
+	fprintTitle(color.Output, title)
+}
+
+func (data Data) printTitle(title string) {
+	fprintTitle(data.getOutput(), title)
+}
+
+func fprintTitle(output io.Writer, title string) {
+	fmt.Fprintln(output, color.HiCyanString("\n"+title))
+	fmt.Fprintln(output, strings.Repeat("=", len(title)))
 }
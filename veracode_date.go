@@ -1,19 +1,27 @@
 package main
 
 import (
-	"os"
+	"fmt"
 	"time"
-
-	"github.com/fatih/color"
 )
 
-func parseVeracodeDate(date string) time.Time {
+func parseVeracodeDate(date string) (time.Time, error) {
+	parsed, err := time.Parse("2006-01-02 15:04:05 MST", date)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Could not parse \"%s\" as a date", date)
+	}
+
+	return parsed, nil
+}
+
+// Returns a zero time if the date is missing or cannot be parsed
+func tryParseVeracodeDate(date string) time.Time {
 	parsed, err := time.Parse("2006-01-02 15:04:05 MST", date)
 
 	if err != nil {
-		color.HiRed("Error: Could not parse \"%s\" as a date", date)
-		os.Exit(1)
+		return time.Time{}
 	}
 
-	return parsed
+	return parsed.Local()
 }